@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/redis.v2"
+)
+
+// Blocking commands are applied on dedicated connections. They are
+// interrupted after the block timeout plus this margin.
+const blockingMargin = time.Second
+
+// BLPop removes and returns the first element of the first non-empty list,
+// blocks for up to timeout seconds. All keys must hash to the same slot.
+// A zero timeout blocks indefinitely.
+func (c *Client) BLPop(timeout int64, keys ...string) *redis.StringSliceCmd {
+	args := append([]string{"BLPOP"}, keys...)
+	args = append(args, strconv.FormatInt(timeout, 10))
+	cmd := redis.NewStringSliceCmd(args...)
+	c.processBlocking(HashSlot(firstKey(keys)), cmd, timeout)
+	return cmd
+}
+
+// BRPop removes and returns the last element of the first non-empty list,
+// blocks for up to timeout seconds. All keys must hash to the same slot.
+// A zero timeout blocks indefinitely.
+func (c *Client) BRPop(timeout int64, keys ...string) *redis.StringSliceCmd {
+	args := append([]string{"BRPOP"}, keys...)
+	args = append(args, strconv.FormatInt(timeout, 10))
+	cmd := redis.NewStringSliceCmd(args...)
+	c.processBlocking(HashSlot(firstKey(keys)), cmd, timeout)
+	return cmd
+}
+
+// BRPopLPush moves the last element of source to destination, blocks for
+// up to timeout seconds. Both keys must hash to the same slot. A zero
+// timeout blocks indefinitely.
+func (c *Client) BRPopLPush(source, destination string, timeout int64) *redis.StringCmd {
+	cmd := redis.NewStringCmd("BRPOPLPUSH", source, destination, strconv.FormatInt(timeout, 10))
+	c.processBlocking(HashSlot(source), cmd, timeout)
+	return cmd
+}
+
+// Applies a blocking command to the master of a hash slot. Follows
+// redirects, including those received while blocked, e.g. when the
+// slot is migrated or the master is demoted.
+func (c *Client) processBlocking(hashSlot int, cmd redis.Cmder, timeout int64) {
+	if err := c.ctx.Err(); err != nil {
+		setCmdErr(cmd, err)
+		return
+	}
+	if c.reloadDue() {
+		c.reload(c.ctx)
+	}
+
+	addr, ask := c.topo().slotAddr(hashSlot), false
+	for attempt := 0; attempt < MaxRedirects; attempt++ {
+		if attempt > 0 {
+			if err := c.ctx.Err(); err != nil {
+				setCmdErr(cmd, err)
+				return
+			}
+			cmd.Reset()
+		}
+
+		c.block(addr, cmd, timeout, ask)
+		err := cmd.Err()
+		if err == nil || err == redis.Nil {
+			return
+		}
+
+		// Demoted masters unblock their clients, reload the slot cache
+		// once it is due
+		if isUnblocked(err) {
+			if err := c.awaitReload(); err != nil {
+				setCmdErr(cmd, err)
+				return
+			}
+			if c.reloadDue() {
+				c.reload(c.ctx)
+			}
+			addr, ask = c.topo().slotAddr(hashSlot), false
+			continue
+		}
+
+		switch e := parseError(err).(type) {
+		case *MovedError:
+			c.forceReloadOnNextCommand()
+			addr, ask = c.opts.mapAddr(e.Addr), false
+		case *AskError:
+			addr, ask = c.opts.mapAddr(e.Addr), true
+		default:
+			wrapCmdErr(cmd, addr)
+			return
+		}
+	}
+	wrapCmdErr(cmd, addr)
+}
+
+// Applies a blocking command on a dedicated connection, which is
+// interrupted when the client's context is done or the block timeout
+// has passed. Connections are kept for later blocking commands.
+func (c *Client) block(addr string, cmd redis.Cmder, timeout int64, ask bool) {
+	conn, err := c.blocking.Fetch(c.ctx, addr, c.connectBlocking)
+	if err != nil {
+		setCmdErr(cmd, err)
+		return
+	}
+
+	ctx := c.ctx
+	if d := blockingReadTimeout(timeout); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	unbind := conn.bind(ctx)
+	applyCmd(conn.Client, cmd, ask)
+	unbind()
+
+	if err := c.ctx.Err(); err != nil && cmd.Err() != nil {
+		setCmdErr(cmd, err)
+	}
+
+	c.blocking.Put(conn)
+}
+
+// Connect to an address with a blocking connection, without read timeout
+func (c *Client) connectBlocking(addr string) *boundConn {
+	opts := c.opts.options(addr)
+	opts.ReadTimeout = 0
+	return newBoundConn(opts, c.opts.dialTimeout())
+}
+
+// Returns the read timeout for a block timeout in seconds,
+// zero blocks indefinitely
+func blockingReadTimeout(sec int64) time.Duration {
+	if sec <= 0 {
+		return 0
+	}
+	return time.Duration(sec)*time.Second + blockingMargin
+}
+
+// Is err an UNBLOCKED reply
+func isUnblocked(err error) bool {
+	return strings.HasPrefix(err.Error(), "UNBLOCKED ")
+}
@@ -0,0 +1,93 @@
+package cluster
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Blocking commands", func() {
+	fake := newFakeCluster()
+
+	BeforeEach(func() {
+		fake.route(fake.nodes[0], fake.nodes[0])
+	})
+
+	It("should calculate read timeouts", func() {
+		Expect(blockingReadTimeout(0)).To(Equal(time.Duration(0)))
+		Expect(blockingReadTimeout(5)).To(Equal(6 * time.Second))
+	})
+
+	It("should pop from lists", func() {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"BLPOP", "{q}a", "{q}b", "1"})))
+			fake.nodes[0].Reply("*2\r\n$4\r\n{q}b\r\n$3\r\njob\r\n")
+		}()
+		Expect(fake.client.BLPop(1, "{q}a", "{q}b").Val()).To(Equal([]string{"{q}b", "job"}))
+	})
+
+	It("should follow redirects received while blocked", func() {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"BRPOPLPUSH", "{q}in", "{q}busy", "0"})))
+			fake.nodes[0].Reply("-MOVED 5443 " + fake.nodes[1].Addr() + "\r\n")
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"BRPOPLPUSH", "{q}in", "{q}busy", "0"})))
+			fake.nodes[1].Reply("$3\r\njob\r\n")
+		}()
+
+		cmd := fake.client.BRPopLPush("{q}in", "{q}busy", 0)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal("job"))
+	})
+
+	It("should retry after unblocks", func() {
+		atomic.StoreInt64(&fake.client.reloadedAt, time.Now().UnixNano())
+		start := time.Now()
+
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"BRPOP", "q", "0"})))
+			fake.nodes[0].Reply("-UNBLOCKED force unblock from blocking operation, instance state changed (master -> replica?)\r\n")
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"BRPOP", "q", "0"})))
+			fake.nodes[0].Reply("*2\r\n$1\r\nq\r\n$3\r\njob\r\n")
+		}()
+		Expect(fake.client.BRPop(0, "q").Val()).To(Equal([]string{"q", "job"}))
+
+		// Reloads are rate-limited
+		Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+	})
+
+	It("should stop blocking when the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		Expect(fake.client.WithContext(ctx).BLPop(0, "q").Err()).To(Equal(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(fake.client.blocking.nodes[fake.nodes[0].Addr()].idle).To(BeEmpty())
+	})
+
+	It("should reuse connections", func() {
+		accepted := func() int {
+			fake.nodes[0].mu.Lock()
+			defer fake.nodes[0].mu.Unlock()
+			return len(fake.nodes[0].conns)
+		}
+
+		for i := 0; i < 3; i++ {
+			go func() {
+				defer GinkgoRecover()
+				Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"BLPOP", "q", "1"})))
+				fake.nodes[0].Reply("*2\r\n$1\r\nq\r\n$3\r\njob\r\n")
+			}()
+			Expect(fake.client.BLPop(1, "q").Val()).To(Equal([]string{"q", "job"}))
+		}
+		Expect(accepted()).To(Equal(1))
+		Expect(fake.client.blocking.nodes[fake.nodes[0].Addr()].idle).To(HaveLen(1))
+	})
+
+})
@@ -0,0 +1,246 @@
+package cluster
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"gopkg.in/redis.v2"
+)
+
+// boundConn is a single connection to a node. Its I/O can be interrupted
+// when a context is done, which redis.v2 pools do not support.
+type boundConn struct {
+	*redis.Client
+	node *boundNode // pooled connections only
+
+	mu          sync.Mutex
+	cn          net.Conn
+	done        <-chan struct{}
+	interrupted bool
+}
+
+func newBoundConn(opts *redis.Options, dialTimeout time.Duration) *boundConn {
+	b := new(boundConn)
+
+	dial := opts.Dialer
+	if dial == nil {
+		addr := opts.Addr
+		dial = func() (net.Conn, error) {
+			return net.DialTimeout("tcp", addr, dialTimeout)
+		}
+	}
+	opts.PoolSize = 1
+	opts.Dialer = func() (net.Conn, error) {
+		cn, err := dial()
+		if err != nil {
+			return nil, err
+		}
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.cn = cn
+		if b.done != nil {
+			select {
+			case <-b.done:
+				b.interrupt()
+			default:
+			}
+		}
+		return cn, nil
+	}
+
+	b.Client = redis.NewTCPClient(opts)
+	return b
+}
+
+// Interrupts the connection's I/O once ctx is done, until the returned
+// function is called
+func (b *boundConn) bind(ctx context.Context) (unbind func()) {
+	done := ctx.Done()
+	if done == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	b.done = done
+	b.mu.Unlock()
+
+	stop, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		select {
+		case <-done:
+			b.mu.Lock()
+			b.interrupt()
+			b.mu.Unlock()
+		case <-stop:
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-exited
+
+		b.mu.Lock()
+		b.done = nil
+		b.mu.Unlock()
+	}
+}
+
+// Was the connection interrupted
+func (b *boundConn) isInterrupted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.interrupted
+}
+
+// Expires the deadline of the network connection, requires mu
+func (b *boundConn) interrupt() {
+	b.interrupted = true
+	if b.cn != nil {
+		b.cn.SetDeadline(time.Now())
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// boundPool keeps bound connections, per node. Up to size connections
+// per node are kept idle. If limited, at most size connections per node
+// are open at a time, callers wait for one to be returned.
+type boundPool struct {
+	size    int
+	limited bool
+	nodes   map[string]*boundNode
+
+	sync.Mutex
+}
+
+// boundNode holds the connections to a single node
+type boundNode struct {
+	addr string
+	idle chan *boundConn
+	open chan struct{} // a token per open connection, nil if unlimited
+}
+
+func newBoundPool(size int, limited bool) *boundPool {
+	if size < 1 {
+		size = 10
+	}
+	return &boundPool{
+		size:    size,
+		limited: limited,
+		nodes:   make(map[string]*boundNode),
+	}
+}
+
+// Fetch gets an idle or creates a new connection. When the limit
+// is reached, waits for a connection until ctx is done.
+func (p *boundPool) Fetch(ctx context.Context, addr string, newConn func(string) *boundConn) (*boundConn, error) {
+	node := p.node(addr)
+
+	select {
+	case conn := <-node.idle:
+		return conn, nil
+	default:
+	}
+
+	if node.open != nil {
+		select {
+		case conn := <-node.idle:
+			return conn, nil
+		case node.open <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	conn := newConn(addr)
+	conn.node = node
+	return conn, nil
+}
+
+// Put returns a connection to the pool, closes interrupted connections,
+// those of removed nodes and those in excess of the pool size
+func (p *boundPool) Put(conn *boundConn) {
+	node := conn.node
+	if !conn.isInterrupted() {
+		p.Lock()
+		if p.nodes[node.addr] == node {
+			select {
+			case node.idle <- conn:
+				p.Unlock()
+				return
+			default:
+			}
+		}
+		p.Unlock()
+	}
+
+	conn.Close()
+	node.release()
+}
+
+// Remove closes the idle connections to addr
+func (p *boundPool) Remove(addr string) {
+	p.Lock()
+	node := p.nodes[addr]
+	delete(p.nodes, addr)
+	p.Unlock()
+
+	if node != nil {
+		node.close()
+	}
+}
+
+// Clear closes all idle connections
+func (p *boundPool) Clear() {
+	p.Lock()
+	nodes := p.nodes
+	p.nodes = make(map[string]*boundNode)
+	p.Unlock()
+
+	for _, node := range nodes {
+		node.close()
+	}
+}
+
+// Returns the connections of a node, creates them if necessary
+func (p *boundPool) node(addr string) *boundNode {
+	p.Lock()
+	defer p.Unlock()
+
+	node, ok := p.nodes[addr]
+	if !ok {
+		node = &boundNode{addr: addr, idle: make(chan *boundConn, p.size)}
+		if p.limited {
+			node.open = make(chan struct{}, p.size)
+		}
+		p.nodes[addr] = node
+	}
+	return node
+}
+
+// Closes the idle connections
+func (n *boundNode) close() {
+	for {
+		select {
+		case conn := <-n.idle:
+			conn.Close()
+			n.release()
+		default:
+			return
+		}
+	}
+}
+
+// Releases the token of a closed connection
+func (n *boundNode) release() {
+	if n.open != nil {
+		<-n.open
+	}
+}
@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("boundPool", func() {
+	var subject *boundPool
+	var node *fakeNode
+
+	newConn := func(addr string) *boundConn {
+		return newBoundConn(&redis.Options{Addr: addr}, 0)
+	}
+	fetch := func(pool *boundPool) *boundConn {
+		conn, err := pool.Fetch(context.Background(), node.Addr(), newConn)
+		Expect(err).NotTo(HaveOccurred())
+		return conn
+	}
+
+	BeforeEach(func() {
+		node = newFakeNode()
+		subject = newBoundPool(1, true)
+	})
+
+	AfterEach(func() {
+		subject.Clear()
+		node.Close()
+	})
+
+	It("should reuse idle connections", func() {
+		conn := fetch(subject)
+		subject.Put(conn)
+		Expect(fetch(subject)).To(BeIdenticalTo(conn))
+	})
+
+	It("should limit open connections", func() {
+		conn := fetch(subject)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := subject.Fetch(ctx, node.Addr(), newConn)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		time.AfterFunc(20*time.Millisecond, func() { subject.Put(conn) })
+		Expect(fetch(subject)).To(BeIdenticalTo(conn))
+	})
+
+	It("should limit idle connections", func() {
+		pool := newBoundPool(1, false)
+		defer pool.Clear()
+
+		conn1, conn2 := fetch(pool), fetch(pool)
+		pool.Put(conn1)
+		pool.Put(conn2)
+		Expect(pool.nodes[node.Addr()].idle).To(HaveLen(1))
+
+		pool.Remove(node.Addr())
+		Expect(pool.nodes).To(BeEmpty())
+	})
+
+	It("should discard interrupted connections", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		conn := fetch(subject)
+		unbind := conn.bind(ctx)
+		cancel()
+		Eventually(conn.isInterrupted).Should(BeTrue())
+		unbind()
+
+		subject.Put(conn)
+		Expect(subject.nodes[node.Addr()].idle).To(BeEmpty())
+		Expect(fetch(subject)).NotTo(BeIdenticalTo(conn))
+	})
+
+	It("should close connections returned after removal", func() {
+		conn := fetch(subject)
+		subject.Remove(node.Addr())
+		subject.Put(conn)
+		Expect(subject.nodes).To(BeEmpty())
+
+		Expect(fetch(subject)).NotTo(BeIdenticalTo(conn))
+	})
+
+})
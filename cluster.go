@@ -1,13 +1,14 @@
 package cluster
 
 import (
+	"context"
 	"errors"
 	"io"
 	"math/rand"
 	"net"
-	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"gopkg.in/redis.v2"
 )
@@ -20,25 +21,51 @@ const (
 var errNoAddresses = errors.New("redis cluster: missing addresses")
 
 type Client struct {
-	addrs []string
-	opts  *Options
+	commandable
+	*clientState
 
-	slots [][]string
-	conns *connLRU
+	ctx context.Context
+}
+
+// clientState is shared by a Client and all its context views
+type clientState struct {
+	opts *Options
+
+	topology atomic.Value // *topology
+	conns    *connLRU
+	bound    *boundPool // connections of commands with cancellable contexts
+	blocking *boundPool // dedicated connections of blocking commands
+	latency  *latencyTracker
 
 	forceReload uint32
+	readIndex   uint32
+	reloadedAt  int64 // unix nanoseconds of the last reload
+	reloading   *reloadCall
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	watchers  map[chan<- struct{}]struct{}
 
-	lock sync.RWMutex
+	lock sync.Mutex // guards slot cache updates and reloading
+}
+
+// reloadCall is a reload in progress, shared by concurrent callers
+type reloadCall struct {
+	done chan struct{}
+	err  error
 }
 
 // Connect connects to a cluster, using a list of seeds
 func Connect(opts *Options) (*Client, error) {
 	client := newClient(opts)
-	if err := client.reload(); err != nil {
+	if err := client.reload(context.Background()); err != nil {
 		return nil, err
-	} else if len(client.addrs) < 1 {
+	} else if len(client.topo().addrs) < 1 {
 		return nil, errNoAddresses
 	}
+	if opts.RefreshInterval > 0 {
+		go client.refreshLoop(opts.RefreshInterval)
+	}
 	return client, nil
 }
 
@@ -46,15 +73,48 @@ func newClient(opts *Options) *Client {
 	if opts == nil {
 		opts = &Options{}
 	}
-	return &Client{
-		addrs: opts.Addrs,
-		opts:  opts,
-		conns: newLRU(opts.maxConns()),
+	state := &clientState{
+		opts:     opts,
+		conns:    newLRU(opts.maxConns()),
+		bound:    newBoundPool(opts.PoolSize, true),
+		blocking: newBoundPool(opts.PoolSize, false),
+		closing:  make(chan struct{}),
+	}
+	if opts.RouteByLatency {
+		state.latency = newLatencyTracker()
+	}
+	state.topology.Store(newTopology(opts.Addrs))
+	return state.client(context.Background())
+}
+
+// Creates a client view with a context
+func (s *clientState) client(ctx context.Context) *Client {
+	client := &Client{clientState: s, ctx: ctx}
+	client.commandable.process = client.processCmd
+	return client
+}
+
+// WithContext returns a view of the client which applies all commands
+// with ctx. The context is checked before every attempt, i.e. across
+// redirects, retries and slot cache reloads, and interrupts commands
+// that are in flight. Connections and the slot cache are shared with
+// the original client.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("redis cluster: nil context")
 	}
+	return c.clientState.client(ctx)
 }
 
-// Close closes all cached connections
+// Context returns the client's context
+func (c *Client) Context() context.Context {
+	return c.ctx
+}
+
+// Close stops the background refresh and closes all cached connections
 func (c *Client) Close() error {
+	c.closeOnce.Do(func() { close(c.closing) })
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -62,84 +122,195 @@ func (c *Client) Close() error {
 	return nil
 }
 
-// Process applies a single command to a hashSlot
-func (c *Client) Process(hashSlot int, cmd redis.Cmder) {
+// Applies a single command to a hashSlot, read-only commands
+// may be routed to replicas. Retries transient errors according
+// to the retry policy
+func (c *Client) processCmd(hashSlot int, cmd redis.Cmder, readOnly bool) {
+	retry := c.opts.newRetry(c.ctx)
+	for {
+		addr := c.tryCmd(hashSlot, cmd, readOnly)
+		if err := cmd.Err(); isRetryable(err) && retry.Wait() {
+			if !isTryAgain(err) {
+				c.forceReloadOnNextCommand()
+			}
+			cmd.Reset()
+			continue
+		}
+
+		wrapCmdErr(cmd, addr)
+		return
+	}
+}
+
+// Applies a single command to a hashSlot, follows redirects and
+// falls back to other nodes on connection errors. Returns the address
+// of the node that served the last attempt
+func (c *Client) tryCmd(hashSlot int, cmd redis.Cmder, readOnly bool) string {
+	if err := c.ctx.Err(); err != nil {
+		setCmdErr(cmd, err)
+		return ""
+	}
 	if c.reloadDue() {
-		c.reload()
+		c.reload(c.ctx)
 	}
 
 	ask := false
 
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
-	tried := make(map[string]struct{}, len(c.addrs))
-	addr := c.slotAddr(hashSlot)
+	topo := c.topo()
+	tried := make(map[string]struct{}, len(topo.addrs))
+	addr, failed := topo.slotAddr(hashSlot), ""
+	if readOnly {
+		addr = c.slotReadAddr(topo, hashSlot)
+	}
 	for attempt := 0; attempt < MaxRedirects; attempt++ {
 		tried[addr] = struct{}{}
+		if attempt > 0 {
+			if err := c.ctx.Err(); err != nil {
+				setCmdErr(cmd, err)
+				return addr
+			}
+			cmd.Reset()
+		}
 
 		// Pick the connection, process request
-		conn := c.conns.Fetch(addr, c.connectTo)
-		if ask {
-			pipe := conn.Pipeline()
-			pipe.Process(redis.NewCmd("ASKING"))
-			pipe.Process(cmd)
-			_, _ = pipe.Exec()
-			ask = false
-		} else {
-			conn.Process(cmd)
+		start := time.Now()
+		c.processOn(addr, cmd, ask)
+		ask = false
+
+		// Stop when interrupted by the context
+		err := cmd.Err()
+		if err != nil && c.ctx.Err() != nil {
+			return addr
+		}
+
+		// Track round-trip times of reachable nodes
+		if c.latency != nil && !isConnError(err) {
+			c.latency.Observe(addr, time.Since(start))
 		}
 
 		// If there is no (real) error, we are done!
-		err := cmd.Err()
 		if err == nil || err == redis.Nil {
-			return
+			return addr
 		}
 
 		// On connection errors, pick the next (not previosuly) tried connection
 		// and try again
-		if _, ok := err.(*net.OpError); ok || err == io.EOF {
-			if addr = c.nextAddr(tried); addr == "" {
-				return
+		if isConnError(err) {
+			next := topo.retryAddr(hashSlot, tried)
+			if next == "" {
+				return addr
 			}
-			cmd.Reset()
+			addr = next
 			continue
 		}
 
-		// Check the error message, return if unexpected
-		parts := strings.SplitN(err.Error(), " ", 3)
-		if len(parts) != 3 {
-			return
-		}
-
 		// Handle MOVE and ASK redirections, return on any other error
-		switch parts[0] {
-		case "MOVED":
+		switch e := parseError(err).(type) {
+		case *MovedError:
 			c.forceReloadOnNextCommand()
-			addr = parts[2]
-		case "ASK":
+			failed, addr = addr, c.opts.mapAddr(e.Addr)
+		case *AskError:
 			ask = true
-			addr = parts[2]
+			failed, addr = addr, c.opts.mapAddr(e.Addr)
 		default:
-			return
+			return addr
 		}
-		cmd.Reset()
 	}
+
+	// Too many redirects
+	return failed
 }
 
-// Closes all connections and reloads slot cache
-func (c *Client) reload() (err error) {
+// Applies a command to a node, preceded by ASKING if ask is set. Unless
+// the client's context can never be done, a bound connection is used,
+// which interrupts the command when the context is done.
+func (c *Client) processOn(addr string, cmd redis.Cmder, ask bool) {
+	if c.ctx.Done() == nil {
+		applyCmd(c.conns.Fetch(addr, c.connectTo), cmd, ask)
+		return
+	}
+
+	conn, err := c.bound.Fetch(c.ctx, addr, c.connectBound)
+	if err != nil {
+		setCmdErr(cmd, err)
+		return
+	}
+
+	unbind := conn.bind(c.ctx)
+	applyCmd(conn.Client, cmd, ask)
+	unbind()
+
+	if err := c.ctx.Err(); err != nil && cmd.Err() != nil {
+		setCmdErr(cmd, err)
+	}
+
+	c.bound.Put(conn)
+}
+
+// Reloads the slot cache. Concurrent calls wait for the reload
+// in progress and share its result. The reload itself is detached from
+// ctx, only the wait is bounded by it.
+func (c *Client) reload(ctx context.Context) error {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	call := c.reloading
+	if call == nil {
+		call = &reloadCall{done: make(chan struct{})}
+		c.reloading = call
+		go c.runReload(call)
+	}
+	c.lock.Unlock()
 
-	for _, addr := range c.addrs {
-		c.reset()
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Runs a shared reload, until the client is closed
+func (c *Client) runReload(call *reloadCall) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-c.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	call.err = c.fetchSlots(ctx)
+	atomic.StoreInt64(&c.reloadedAt, time.Now().UnixNano())
+
+	c.lock.Lock()
+	c.reloading = nil
+	c.lock.Unlock()
+
+	close(call.done)
+}
+
+// Fetches the slot table from the first reachable node and caches it
+func (c *Client) fetchSlots(ctx context.Context) (err error) {
+	for _, addr := range c.topo().addrs {
+		if err = ctx.Err(); err != nil {
+			return
+		}
 
 		var infos []slotInfo
-		if infos, err = c.clusterSlots(addr); err == nil {
-			c.cacheSlots(infos)
-			break
+		if infos, err = c.clusterSlots(addr); err != nil {
+			continue
+		}
+
+		c.lock.Lock()
+		event := c.cacheSlots(infos)
+		c.lock.Unlock()
+
+		if event != nil {
+			c.opts.OnTopologyChange(event)
 		}
+		return nil
 	}
 	return
 }
@@ -147,36 +318,51 @@ func (c *Client) reload() (err error) {
 // Closes all connections and flushes slots cache
 func (c *Client) reset() {
 	c.conns.Clear()
-	c.slots = make([][]string, HashSlots)
+	c.bound.Clear()
+	c.blocking.Clear()
+	c.topology.Store(newTopology(c.topo().addrs))
 }
 
-// Set slots cache
-func (c *Client) cacheSlots(infos []slotInfo) {
-	// Create a map of known nodes
-	known := make(map[string]struct{}, len(c.addrs))
-	for _, addr := range c.addrs {
-		known[addr] = struct{}{}
+// Set slots cache, if it differs from the current one. Translates
+// node addresses and closes connections to nodes that left the cluster.
+// Returns a change event for OnTopologyChange, unless the previous
+// cache was empty
+func (c *Client) cacheSlots(infos []slotInfo) *TopologyEvent {
+	mapped := make([]slotInfo, len(infos))
+	for i, info := range infos {
+		mapped[i] = slotInfo{min: info.min, max: info.max, addrs: make([]string, len(info.addrs))}
+		for n, addr := range info.addrs {
+			mapped[i].addrs[n] = c.opts.mapAddr(addr)
+		}
+	}
+
+	prev := c.topo()
+	next := prev.withSlots(mapped)
+	if equalSlots(prev.slots, next.slots) {
+		return nil
 	}
+	c.topology.Store(next)
 
-	// Populate slots, store unknown nodes
-	for _, info := range infos {
-		for i := info.min; i <= info.max; i++ {
-			c.slots[i] = info.addrs
+	for ch := range c.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
 		}
+	}
 
-		for _, addr := range info.addrs {
-			if _, ok := known[addr]; !ok {
-				c.addrs = append(c.addrs, addr)
-				known[addr] = struct{}{}
-			}
+	prevNodes, nodes := prev.nodes(), next.nodes()
+	for addr := range prevNodes {
+		if _, ok := nodes[addr]; !ok {
+			c.conns.Remove(addr)
+			c.bound.Remove(addr)
+			c.blocking.Remove(addr)
 		}
 	}
 
-	// Shuffle addresses
-	for i := range c.addrs {
-		j := rand.Intn(i + 1)
-		c.addrs[i], c.addrs[j] = c.addrs[j], c.addrs[i]
+	if c.opts.OnTopologyChange == nil || len(prevNodes) == 0 {
+		return nil
 	}
+	return newTopologyEvent(prev, next)
 }
 
 func (c *Client) clusterSlots(addr string) ([]slotInfo, error) {
@@ -198,32 +384,94 @@ func (c *Client) connectTo(addr string) *redis.Client {
 	return redis.NewTCPClient(c.opts.options(addr))
 }
 
+// Connect to an address with a bound connection
+func (c *Client) connectBound(addr string) *boundConn {
+	return newBoundConn(c.opts.options(addr), c.opts.dialTimeout())
+}
+
+// Applies a command to a connection, preceded by ASKING if ask is set
+func applyCmd(conn *redis.Client, cmd redis.Cmder, ask bool) {
+	if !ask {
+		conn.Process(cmd)
+		return
+	}
+
+	pipe := conn.Pipeline()
+	pipe.Process(redis.NewCmd("ASKING"))
+	pipe.Process(cmd)
+	_, _ = pipe.Exec()
+	pipe.Close()
+}
+
 // Forces a cache reload on next request
 func (c *Client) forceReloadOnNextCommand() {
 	atomic.StoreUint32(&c.forceReload, 1)
 }
 
-// Is a cache reload due
+// Is a cache reload due. Reloads are at least MinReloadInterval
+// apart, forced reloads are delayed until then
 func (c *Client) reloadDue() bool {
+	if atomic.LoadUint32(&c.forceReload) == 0 {
+		return false
+	}
+	if since := time.Now().UnixNano() - atomic.LoadInt64(&c.reloadedAt); since < int64(c.opts.minReloadInterval()) {
+		return false
+	}
 	return atomic.CompareAndSwapUint32(&c.forceReload, 1, 0)
 }
 
-// Find the current address for a hash slot
-func (c *Client) slotAddr(hashSlot int) string {
-	if len(c.slots) == HashSlots {
-		if addrs := c.slots[hashSlot]; len(addrs) > 0 {
-			return addrs[0]
-		}
+// Forces a cache reload and waits until it is due, returns early
+// when the context is done
+func (c *Client) awaitReload() error {
+	c.forceReloadOnNextCommand()
+
+	wait := atomic.LoadInt64(&c.reloadedAt) + int64(c.opts.minReloadInterval()) - time.Now().UnixNano()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(wait))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.ctx.Done():
+		return c.ctx.Err()
 	}
-	return ""
 }
 
-// Find the next untried address
-func (c *Client) nextAddr(tried map[string]struct{}) string {
-	for _, addr := range c.addrs {
-		if _, ok := tried[addr]; !ok {
-			return addr
-		}
+// Is err a network/connection error
+func isConnError(err error) bool {
+	_, ok := err.(*net.OpError)
+	return ok || err == io.EOF
+}
+
+// Is err a network timeout
+func isTimeout(err error) bool {
+	e, ok := err.(net.Error)
+	return ok && e.Timeout()
+}
+
+// Find an address for a read-only command on a hash slot,
+// according to the configured ReadMode or RouteByLatency
+func (c *Client) slotReadAddr(topo *topology, hashSlot int) string {
+	addrs := topo.slots[hashSlot]
+	if len(addrs) == 0 {
+		return ""
+	} else if len(addrs) == 1 {
+		return addrs[0]
+	} else if c.latency != nil {
+		return c.latency.Fastest(addrs)
+	}
+
+	replicas := addrs[1:]
+	switch c.opts.ReadMode {
+	case ReadRandomReplica:
+		return replicas[rand.Intn(len(replicas))]
+	case ReadRoundRobinReplica:
+		n := atomic.AddUint32(&c.readIndex, 1) - 1
+		return replicas[int(n%uint32(len(replicas)))]
 	}
-	return ""
+	return addrs[0]
 }
@@ -1,8 +1,12 @@
 package cluster
 
 import (
+	"context"
+	"errors"
+	"net"
 	"sort"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -33,40 +37,157 @@ var _ = Describe("Client", func() {
 	It("should reset slots cache an connections", func() {
 		populate()
 		subject.conns.Fetch("127.0.0.1:7003", subject.connectTo)
-		Expect(subject.slots).To(HaveLen(HashSlots))
-		Expect(subject.slots[0]).To(HaveLen(2))
+		Expect(subject.topo().slots).To(HaveLen(HashSlots))
+		Expect(subject.topo().slots[0]).To(HaveLen(2))
 		Expect(subject.conns.len()).To(Equal(1))
 
 		subject.reset()
-		Expect(subject.slots).To(HaveLen(HashSlots))
-		Expect(subject.slots[0]).To(BeEmpty())
+		Expect(subject.topo().slots).To(HaveLen(HashSlots))
+		Expect(subject.topo().slots[0]).To(BeEmpty())
 		Expect(subject.conns.len()).To(Equal(0))
 	})
 
 	It("should populate slots cache", func() {
 		populate()
-		Expect(subject.slots[0]).To(Equal([]string{"127.0.0.1:7000", "127.0.0.1:7004"}))
-		Expect(subject.slots[4095]).To(Equal([]string{"127.0.0.1:7000", "127.0.0.1:7004"}))
-		Expect(subject.slots[4096]).To(Equal([]string{"127.0.0.1:7001", "127.0.0.1:7005"}))
-		Expect(subject.slots[8191]).To(Equal([]string{"127.0.0.1:7001", "127.0.0.1:7005"}))
-		Expect(subject.slots[8192]).To(Equal([]string{"127.0.0.1:7002", "127.0.0.1:7006"}))
-		Expect(subject.slots[12287]).To(Equal([]string{"127.0.0.1:7002", "127.0.0.1:7006"}))
-		Expect(subject.slots[12288]).To(Equal([]string{"127.0.0.1:7003", "127.0.0.1:7007"}))
-		Expect(subject.slots[16383]).To(Equal([]string{"127.0.0.1:7003", "127.0.0.1:7007"}))
+		Expect(subject.topo().slots[0]).To(Equal([]string{"127.0.0.1:7000", "127.0.0.1:7004"}))
+		Expect(subject.topo().slots[4095]).To(Equal([]string{"127.0.0.1:7000", "127.0.0.1:7004"}))
+		Expect(subject.topo().slots[4096]).To(Equal([]string{"127.0.0.1:7001", "127.0.0.1:7005"}))
+		Expect(subject.topo().slots[8191]).To(Equal([]string{"127.0.0.1:7001", "127.0.0.1:7005"}))
+		Expect(subject.topo().slots[8192]).To(Equal([]string{"127.0.0.1:7002", "127.0.0.1:7006"}))
+		Expect(subject.topo().slots[12287]).To(Equal([]string{"127.0.0.1:7002", "127.0.0.1:7006"}))
+		Expect(subject.topo().slots[12288]).To(Equal([]string{"127.0.0.1:7003", "127.0.0.1:7007"}))
+		Expect(subject.topo().slots[16383]).To(Equal([]string{"127.0.0.1:7003", "127.0.0.1:7007"}))
 
 		Expect(subject.conns.len()).To(Equal(0))
 
-		Expect(subject.addrs).To(ConsistOf([]string{
+		Expect(subject.topo().addrs).To(ConsistOf([]string{
 			"127.0.0.1:6379",
 			"127.0.0.1:7000", "127.0.0.1:7001", "127.0.0.1:7002", "127.0.0.1:7003",
 			"127.0.0.1:7004", "127.0.0.1:7005", "127.0.0.1:7006", "127.0.0.1:7007",
 		}))
 	})
 
+	It("should swap slots cache snapshots", func() {
+		before := subject.topo()
+		populate()
+		Expect(subject.topo()).NotTo(BeIdenticalTo(before))
+		Expect(before.slots[0]).To(BeEmpty())
+		Expect(before.addrs).To(HaveLen(3))
+	})
+
+	It("should translate node addresses", func() {
+		subject.opts.AddrMap = map[string]string{"127.0.0.1:7000": "localhost:7000"}
+		populate()
+		Expect(subject.topo().slots[0]).To(Equal([]string{"localhost:7000", "127.0.0.1:7004"}))
+		Expect(subject.topo().addrs).To(ContainElement("localhost:7000"))
+		Expect(subject.topo().addrs).NotTo(ContainElement("127.0.0.1:7000"))
+	})
+
+	It("should keep the slots cache if unchanged", func() {
+		populate()
+		before := subject.topo()
+		subject.cacheSlots([]slotInfo{
+			{min: 0, max: 4095, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7004"}},
+			{min: 12288, max: 16383, addrs: []string{"127.0.0.1:7003", "127.0.0.1:7007"}},
+			{min: 4096, max: 8191, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7005"}},
+			{min: 8192, max: 12287, addrs: []string{"127.0.0.1:7002", "127.0.0.1:7006"}},
+		})
+		Expect(subject.topo()).To(BeIdenticalTo(before))
+	})
+
+	It("should only close connections to nodes that left the cluster", func() {
+		populate()
+		subject.conns.Fetch("127.0.0.1:7000", subject.connectTo)
+		subject.conns.Fetch("127.0.0.1:7003", subject.connectTo)
+		Expect(subject.conns.len()).To(Equal(2))
+
+		subject.cacheSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7008", "127.0.0.1:7004"}},
+			{min: 8192, max: 16383, addrs: []string{"127.0.0.1:7003", "127.0.0.1:7007"}},
+		})
+		Expect(subject.conns.len()).To(Equal(1))
+	})
+
+	It("should share reloads in progress", func() {
+		call := &reloadCall{done: make(chan struct{}), err: errors.New("failed")}
+		subject.reloading = call
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(call.done)
+		}()
+		Expect(subject.reload(context.Background())).To(MatchError("failed"))
+
+		call = &reloadCall{done: make(chan struct{})}
+		subject.reloading = call
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		Expect(subject.reload(ctx)).To(Equal(context.DeadlineExceeded))
+	})
+
+	It("should not abort shared reloads with the first caller's context", func() {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer ln.Close()
+
+		client := newClient(&Options{Addrs: []string{ln.Addr().String()}, ReadTimeout: 200 * time.Millisecond})
+		defer client.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		Expect(client.reload(ctx)).To(Equal(context.DeadlineExceeded))
+
+		err = client.reload(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(isTimeout(err)).To(BeTrue())
+	})
+
 	It("should find the current address of a slot", func() {
-		Expect(subject.slotAddr(1000)).To(Equal(""))
+		Expect(subject.topo().slotAddr(1000)).To(Equal(""))
 		populate()
-		Expect(subject.slotAddr(1000)).To(Equal("127.0.0.1:7000"))
+		Expect(subject.topo().slotAddr(1000)).To(Equal("127.0.0.1:7000"))
+	})
+
+	It("should find read addresses of a slot", func() {
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal(""))
+		populate()
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7000"))
+
+		subject.opts.ReadMode = ReadRandomReplica
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7004"))
+
+		subject.cacheSlots([]slotInfo{{min: 1000, max: 1000, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7004", "127.0.0.1:7008"}}})
+		subject.opts.ReadMode = ReadRoundRobinReplica
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7004"))
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7008"))
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7004"))
+
+		subject.cacheSlots([]slotInfo{{min: 1000, max: 1000, addrs: []string{"127.0.0.1:7000"}}})
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7000"))
+	})
+
+	It("should route reads by latency", func() {
+		subject.latency = newLatencyTracker()
+		populate()
+		subject.latency.Observe("127.0.0.1:7000", 2*time.Millisecond)
+		subject.latency.Observe("127.0.0.1:7004", 1*time.Millisecond)
+		Expect(subject.slotReadAddr(subject.topo(), 1000)).To(Equal("127.0.0.1:7004"))
+		Expect(subject.topo().slotAddr(1000)).To(Equal("127.0.0.1:7000"))
+	})
+
+	It("should prefer slot masters when retrying", func() {
+		populate()
+		tried := map[string]struct{}{"127.0.0.1:7004": struct{}{}}
+		Expect(subject.topo().retryAddr(1000, tried)).To(Equal("127.0.0.1:7000"))
+		tried["127.0.0.1:7000"] = struct{}{}
+		Expect(subject.topo().retryAddr(1000, tried)).NotTo(BeElementOf("127.0.0.1:7000", "127.0.0.1:7004", ""))
+	})
+
+	It("should find master addresses", func() {
+		Expect(subject.topo().masterAddrs()).To(BeEmpty())
+		populate()
+		Expect(subject.topo().masterAddrs()).To(ConsistOf([]string{
+			"127.0.0.1:7000", "127.0.0.1:7001", "127.0.0.1:7002", "127.0.0.1:7003",
+		}))
 	})
 
 	It("should find next addresses", func() {
@@ -76,21 +197,69 @@ var _ = Describe("Client", func() {
 			"127.0.0.1:7001": struct{}{},
 			"127.0.0.1:7003": struct{}{},
 		}
-		sort.Strings(subject.addrs)
+		sort.Strings(subject.topo().addrs)
 
-		Expect(subject.nextAddr(seen)).To(Equal("127.0.0.1:6379"))
+		Expect(subject.topo().nextAddr(seen)).To(Equal("127.0.0.1:6379"))
 		seen["127.0.0.1:6379"] = struct{}{}
-		Expect(subject.nextAddr(seen)).To(Equal("127.0.0.1:7002"))
+		Expect(subject.topo().nextAddr(seen)).To(Equal("127.0.0.1:7002"))
 		seen["127.0.0.1:7002"] = struct{}{}
-		Expect(subject.nextAddr(seen)).To(Equal("127.0.0.1:7004"))
+		Expect(subject.topo().nextAddr(seen)).To(Equal("127.0.0.1:7004"))
 		seen["127.0.0.1:7004"] = struct{}{}
-		Expect(subject.nextAddr(seen)).To(Equal("127.0.0.1:7005"))
+		Expect(subject.topo().nextAddr(seen)).To(Equal("127.0.0.1:7005"))
 		seen["127.0.0.1:7005"] = struct{}{}
-		Expect(subject.nextAddr(seen)).To(Equal("127.0.0.1:7006"))
+		Expect(subject.topo().nextAddr(seen)).To(Equal("127.0.0.1:7006"))
 		seen["127.0.0.1:7006"] = struct{}{}
-		Expect(subject.nextAddr(seen)).To(Equal("127.0.0.1:7007"))
+		Expect(subject.topo().nextAddr(seen)).To(Equal("127.0.0.1:7007"))
 		seen["127.0.0.1:7007"] = struct{}{}
-		Expect(subject.nextAddr(seen)).To(Equal(""))
+		Expect(subject.topo().nextAddr(seen)).To(Equal(""))
+	})
+
+	It("should create context views", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		Expect(subject.Context()).To(Equal(context.Background()))
+		view := subject.WithContext(ctx)
+		Expect(view.Context()).To(Equal(ctx))
+		Expect(view.clientState).To(BeIdenticalTo(subject.clientState))
+		Expect(func() { subject.WithContext(nil) }).To(Panic())
+	})
+
+	It("should not process commands with done contexts", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		view := subject.WithContext(ctx)
+		Expect(view.Get("foo").Err()).To(Equal(context.Canceled))
+
+		cmds, err := view.Pipelined(func(pipe *Pipeline) error {
+			pipe.Get("foo")
+			pipe.Get("bar")
+			return nil
+		})
+		Expect(err).To(Equal(context.Canceled))
+		Expect(cmds).To(HaveLen(2))
+		Expect(cmds[1].Err()).To(Equal(context.Canceled))
+	})
+
+	It("should interrupt commands in flight at the context deadline", func() {
+		node := newFakeNode()
+		defer node.Close()
+
+		client := newClient(&Options{})
+		defer client.Close()
+		client.cacheSlots([]slotInfo{{min: 0, max: 16383, addrs: []string{node.Addr()}}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		Expect(client.WithContext(ctx).Get("foo").Err()).To(Equal(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(node.cmds).To(Receive(Equal([]string{"GET", "foo"})))
+
+		// Interrupted connections are not reused
+		Expect(client.bound.nodes[node.Addr()].idle).To(BeEmpty())
 	})
 
 	It("should check if reload is due", func() {
@@ -100,6 +269,16 @@ var _ = Describe("Client", func() {
 		Expect(subject.reloadDue()).To(BeFalse())
 	})
 
+	It("should limit reloads to the minimum interval", func() {
+		subject.reloadedAt = time.Now().UnixNano()
+		subject.forceReloadOnNextCommand()
+		Expect(subject.reloadDue()).To(BeFalse())
+
+		subject.reloadedAt = time.Now().Add(-time.Second).UnixNano()
+		Expect(subject.reloadDue()).To(BeTrue())
+		Expect(subject.reloadDue()).To(BeFalse())
+	})
+
 })
 
 func TestSuite(t *testing.T) {
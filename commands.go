@@ -2,11 +2,29 @@ package cluster
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/redis.v2"
 )
 
+type commandable struct {
+	process func(int, redis.Cmder, bool)
+}
+
+// Process applies a single command to a hashSlot
+func (c *commandable) Process(hashSlot int, cmd redis.Cmder) {
+	c.process(hashSlot, cmd, false)
+}
+
+// ProcessReadOnly applies a single read-only command to a hashSlot.
+// Depending on Options.ReadMode, the command may be served by a replica
+func (c *commandable) ProcessReadOnly(hashSlot int, cmd redis.Cmder) {
+	c.process(hashSlot, cmd, true)
+}
+
+//------------------------------------------------------------------------------
+
 func formatFloat(f float64) string {
 	return strconv.FormatFloat(f, 'f', -1, 64)
 }
@@ -18,43 +36,43 @@ func firstKey(args []string) string {
 	return ""
 }
 
-func (c *Client) Del(keys ...string) *redis.IntCmd {
+func (c *commandable) Del(keys ...string) *redis.IntCmd {
 	cmd := redis.NewIntCmd(append([]string{"DEL"}, keys...)...)
 	c.Process(HashSlot(firstKey(keys)), cmd)
 	return cmd
 }
 
-func (c *Client) Dump(key string) *redis.StringCmd {
+func (c *commandable) Dump(key string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("DUMP", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) Exists(key string) *redis.BoolCmd {
+func (c *commandable) Exists(key string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("EXISTS", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) Expire(key string, dur time.Duration) *redis.BoolCmd {
+func (c *commandable) Expire(key string, dur time.Duration) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("EXPIRE", key, strconv.FormatInt(int64(dur/time.Second), 10))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) ExpireAt(key string, tm time.Time) *redis.BoolCmd {
+func (c *commandable) ExpireAt(key string, tm time.Time) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("EXPIREAT", key, strconv.FormatInt(tm.Unix(), 10))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) PExpire(key string, dur time.Duration) *redis.BoolCmd {
+func (c *commandable) PExpire(key string, dur time.Duration) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("PEXPIRE", key, strconv.FormatInt(int64(dur/time.Millisecond), 10))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) PExpireAt(key string, tm time.Time) *redis.BoolCmd {
+func (c *commandable) PExpireAt(key string, tm time.Time) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd(
 		"PEXPIREAT",
 		key,
@@ -64,33 +82,33 @@ func (c *Client) PExpireAt(key string, tm time.Time) *redis.BoolCmd {
 	return cmd
 }
 
-func (c *Client) PTTL(key string) *redis.DurationCmd {
+func (c *commandable) PTTL(key string) *redis.DurationCmd {
 	cmd := redis.NewDurationCmd(time.Millisecond, "PTTL", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) TTL(key string) *redis.DurationCmd {
+func (c *commandable) TTL(key string) *redis.DurationCmd {
 	cmd := redis.NewDurationCmd(time.Second, "TTL", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) Type(key string) *redis.StatusCmd {
+func (c *commandable) Type(key string) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd("TYPE", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
 //------------------------------------------------------------------------------
 
-func (c *Client) Append(key, value string) *redis.IntCmd {
+func (c *commandable) Append(key, value string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("APPEND", key, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) BitCount(key string, bitCount *redis.BitCount) *redis.IntCmd {
+func (c *commandable) BitCount(key string, bitCount *redis.BitCount) *redis.IntCmd {
 	args := []string{"BITCOUNT", key}
 	if bitCount != nil {
 		args = append(
@@ -100,88 +118,88 @@ func (c *Client) BitCount(key string, bitCount *redis.BitCount) *redis.IntCmd {
 		)
 	}
 	cmd := redis.NewIntCmd(args...)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) Decr(key string) *redis.IntCmd {
+func (c *commandable) Decr(key string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("DECR", key)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) DecrBy(key string, decrement int64) *redis.IntCmd {
+func (c *commandable) DecrBy(key string, decrement int64) *redis.IntCmd {
 	cmd := redis.NewIntCmd("DECRBY", key, strconv.FormatInt(decrement, 10))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) Get(key string) *redis.StringCmd {
+func (c *commandable) Get(key string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("GET", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) GetBit(key string, offset int64) *redis.IntCmd {
+func (c *commandable) GetBit(key string, offset int64) *redis.IntCmd {
 	cmd := redis.NewIntCmd("GETBIT", key, strconv.FormatInt(offset, 10))
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) GetRange(key string, start, end int64) *redis.StringCmd {
+func (c *commandable) GetRange(key string, start, end int64) *redis.StringCmd {
 	cmd := redis.NewStringCmd(
 		"GETRANGE",
 		key,
 		strconv.FormatInt(start, 10),
 		strconv.FormatInt(end, 10),
 	)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) GetSet(key, value string) *redis.StringCmd {
+func (c *commandable) GetSet(key, value string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("GETSET", key, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) Incr(key string) *redis.IntCmd {
+func (c *commandable) Incr(key string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("INCR", key)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) IncrBy(key string, value int64) *redis.IntCmd {
+func (c *commandable) IncrBy(key string, value int64) *redis.IntCmd {
 	cmd := redis.NewIntCmd("INCRBY", key, strconv.FormatInt(value, 10))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) IncrByFloat(key string, value float64) *redis.FloatCmd {
+func (c *commandable) IncrByFloat(key string, value float64) *redis.FloatCmd {
 	cmd := redis.NewFloatCmd("INCRBYFLOAT", key, formatFloat(value))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) MGet(keys ...string) *redis.SliceCmd {
+func (c *commandable) MGet(keys ...string) *redis.SliceCmd {
 	cmd := redis.NewSliceCmd(append([]string{"MGET"}, keys...)...)
-	c.Process(HashSlot(firstKey(keys)), cmd)
+	c.ProcessReadOnly(HashSlot(firstKey(keys)), cmd)
 	return cmd
 }
 
-func (c *Client) MSet(pairs ...string) *redis.StatusCmd {
+func (c *commandable) MSet(pairs ...string) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd(append([]string{"MSET"}, pairs...)...)
 	c.Process(HashSlot(firstKey(pairs)), cmd)
 	return cmd
 }
 
-func (c *Client) MSetNX(pairs ...string) *redis.BoolCmd {
+func (c *commandable) MSetNX(pairs ...string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd(append([]string{"MSETNX"}, pairs...)...)
 	c.Process(HashSlot(firstKey(pairs)), cmd)
 	return cmd
 }
 
-func (c *Client) PSetEx(key string, dur time.Duration, value string) *redis.StatusCmd {
+func (c *commandable) PSetEx(key string, dur time.Duration, value string) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd(
 		"PSETEX",
 		key,
@@ -192,13 +210,13 @@ func (c *Client) PSetEx(key string, dur time.Duration, value string) *redis.Stat
 	return cmd
 }
 
-func (c *Client) Set(key, value string) *redis.StatusCmd {
+func (c *commandable) Set(key, value string) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd("SET", key, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SetBit(key string, offset int64, value int) *redis.IntCmd {
+func (c *commandable) SetBit(key string, offset int64, value int) *redis.IntCmd {
 	cmd := redis.NewIntCmd(
 		"SETBIT",
 		key,
@@ -209,182 +227,182 @@ func (c *Client) SetBit(key string, offset int64, value int) *redis.IntCmd {
 	return cmd
 }
 
-func (c *Client) SetEx(key string, dur time.Duration, value string) *redis.StatusCmd {
+func (c *commandable) SetEx(key string, dur time.Duration, value string) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd("SETEX", key, strconv.FormatInt(int64(dur/time.Second), 10), value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SetNX(key, value string) *redis.BoolCmd {
+func (c *commandable) SetNX(key, value string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("SETNX", key, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SetRange(key string, offset int64, value string) *redis.IntCmd {
+func (c *commandable) SetRange(key string, offset int64, value string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("SETRANGE", key, strconv.FormatInt(offset, 10), value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) StrLen(key string) *redis.IntCmd {
+func (c *commandable) StrLen(key string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("STRLEN", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
 //------------------------------------------------------------------------------
 
-func (c *Client) HDel(key string, fields ...string) *redis.IntCmd {
+func (c *commandable) HDel(key string, fields ...string) *redis.IntCmd {
 	args := append([]string{"HDEL", key}, fields...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HExists(key, field string) *redis.BoolCmd {
+func (c *commandable) HExists(key, field string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("HEXISTS", key, field)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HGet(key, field string) *redis.StringCmd {
+func (c *commandable) HGet(key, field string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("HGET", key, field)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HGetAll(key string) *redis.StringSliceCmd {
+func (c *commandable) HGetAll(key string) *redis.StringSliceCmd {
 	cmd := redis.NewStringSliceCmd("HGETALL", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HGetAllMap(key string) *redis.StringStringMapCmd {
+func (c *commandable) HGetAllMap(key string) *redis.StringStringMapCmd {
 	cmd := redis.NewStringStringMapCmd("HGETALL", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HIncrBy(key, field string, incr int64) *redis.IntCmd {
+func (c *commandable) HIncrBy(key, field string, incr int64) *redis.IntCmd {
 	cmd := redis.NewIntCmd("HINCRBY", key, field, strconv.FormatInt(incr, 10))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HIncrByFloat(key, field string, incr float64) *redis.FloatCmd {
+func (c *commandable) HIncrByFloat(key, field string, incr float64) *redis.FloatCmd {
 	cmd := redis.NewFloatCmd("HINCRBYFLOAT", key, field, formatFloat(incr))
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HKeys(key string) *redis.StringSliceCmd {
+func (c *commandable) HKeys(key string) *redis.StringSliceCmd {
 	cmd := redis.NewStringSliceCmd("HKEYS", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HLen(key string) *redis.IntCmd {
+func (c *commandable) HLen(key string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("HLEN", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HMGet(key string, fields ...string) *redis.SliceCmd {
+func (c *commandable) HMGet(key string, fields ...string) *redis.SliceCmd {
 	args := append([]string{"HMGET", key}, fields...)
 	cmd := redis.NewSliceCmd(args...)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HMSet(key, field, value string, pairs ...string) *redis.StatusCmd {
+func (c *commandable) HMSet(key, field, value string, pairs ...string) *redis.StatusCmd {
 	args := append([]string{"HMSET", key, field, value}, pairs...)
 	cmd := redis.NewStatusCmd(args...)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HSet(key, field, value string) *redis.BoolCmd {
+func (c *commandable) HSet(key, field, value string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("HSET", key, field, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HSetNX(key, field, value string) *redis.BoolCmd {
+func (c *commandable) HSetNX(key, field, value string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("HSETNX", key, field, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) HVals(key string) *redis.StringSliceCmd {
+func (c *commandable) HVals(key string) *redis.StringSliceCmd {
 	cmd := redis.NewStringSliceCmd("HVALS", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
 //------------------------------------------------------------------------------
 
-func (c *Client) LIndex(key string, index int64) *redis.StringCmd {
+func (c *commandable) LIndex(key string, index int64) *redis.StringCmd {
 	cmd := redis.NewStringCmd("LINDEX", key, strconv.FormatInt(index, 10))
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LInsert(key, op, pivot, value string) *redis.IntCmd {
+func (c *commandable) LInsert(key, op, pivot, value string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("LINSERT", key, op, pivot, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LLen(key string) *redis.IntCmd {
+func (c *commandable) LLen(key string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("LLEN", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LPop(key string) *redis.StringCmd {
+func (c *commandable) LPop(key string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("LPOP", key)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LPush(key string, values ...string) *redis.IntCmd {
+func (c *commandable) LPush(key string, values ...string) *redis.IntCmd {
 	args := append([]string{"LPUSH", key}, values...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LPushX(key, value string) *redis.IntCmd {
+func (c *commandable) LPushX(key, value string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("LPUSHX", key, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LRange(key string, start, stop int64) *redis.StringSliceCmd {
+func (c *commandable) LRange(key string, start, stop int64) *redis.StringSliceCmd {
 	cmd := redis.NewStringSliceCmd(
 		"LRANGE",
 		key,
 		strconv.FormatInt(start, 10),
 		strconv.FormatInt(stop, 10),
 	)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LRem(key string, count int64, value string) *redis.IntCmd {
+func (c *commandable) LRem(key string, count int64, value string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("LREM", key, strconv.FormatInt(count, 10), value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LSet(key string, index int64, value string) *redis.StatusCmd {
+func (c *commandable) LSet(key string, index int64, value string) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd("LSET", key, strconv.FormatInt(index, 10), value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) LTrim(key string, start, stop int64) *redis.StatusCmd {
+func (c *commandable) LTrim(key string, start, stop int64) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd(
 		"LTRIM",
 		key,
@@ -395,26 +413,26 @@ func (c *Client) LTrim(key string, start, stop int64) *redis.StatusCmd {
 	return cmd
 }
 
-func (c *Client) RPop(key string) *redis.StringCmd {
+func (c *commandable) RPop(key string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("RPOP", key)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) RPopLPush(source, destination string) *redis.StringCmd {
+func (c *commandable) RPopLPush(source, destination string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("RPOPLPUSH", source, destination)
 	c.Process(HashSlot(source), cmd)
 	return cmd
 }
 
-func (c *Client) RPush(key string, values ...string) *redis.IntCmd {
+func (c *commandable) RPush(key string, values ...string) *redis.IntCmd {
 	args := append([]string{"RPUSH", key}, values...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) RPushX(key string, value string) *redis.IntCmd {
+func (c *commandable) RPushX(key string, value string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("RPUSHX", key, value)
 	c.Process(HashSlot(key), cmd)
 	return cmd
@@ -422,92 +440,92 @@ func (c *Client) RPushX(key string, value string) *redis.IntCmd {
 
 //------------------------------------------------------------------------------
 
-func (c *Client) SAdd(key string, members ...string) *redis.IntCmd {
+func (c *commandable) SAdd(key string, members ...string) *redis.IntCmd {
 	args := append([]string{"SADD", key}, members...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SCard(key string) *redis.IntCmd {
+func (c *commandable) SCard(key string) *redis.IntCmd {
 	cmd := redis.NewIntCmd("SCARD", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SDiff(keys ...string) *redis.StringSliceCmd {
+func (c *commandable) SDiff(keys ...string) *redis.StringSliceCmd {
 	args := append([]string{"SDIFF"}, keys...)
 	cmd := redis.NewStringSliceCmd(args...)
-	c.Process(HashSlot(firstKey(keys)), cmd)
+	c.ProcessReadOnly(HashSlot(firstKey(keys)), cmd)
 	return cmd
 }
 
-func (c *Client) SDiffStore(destination string, keys ...string) *redis.IntCmd {
+func (c *commandable) SDiffStore(destination string, keys ...string) *redis.IntCmd {
 	args := append([]string{"SDIFFSTORE", destination}, keys...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(destination), cmd)
 	return cmd
 }
 
-func (c *Client) SInter(keys ...string) *redis.StringSliceCmd {
+func (c *commandable) SInter(keys ...string) *redis.StringSliceCmd {
 	args := append([]string{"SINTER"}, keys...)
 	cmd := redis.NewStringSliceCmd(args...)
-	c.Process(HashSlot(firstKey(keys)), cmd)
+	c.ProcessReadOnly(HashSlot(firstKey(keys)), cmd)
 	return cmd
 }
 
-func (c *Client) SInterStore(destination string, keys ...string) *redis.IntCmd {
+func (c *commandable) SInterStore(destination string, keys ...string) *redis.IntCmd {
 	args := append([]string{"SINTERSTORE", destination}, keys...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(destination), cmd)
 	return cmd
 }
 
-func (c *Client) SIsMember(key, member string) *redis.BoolCmd {
+func (c *commandable) SIsMember(key, member string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("SISMEMBER", key, member)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SMembers(key string) *redis.StringSliceCmd {
+func (c *commandable) SMembers(key string) *redis.StringSliceCmd {
 	cmd := redis.NewStringSliceCmd("SMEMBERS", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SMove(source, destination, member string) *redis.BoolCmd {
+func (c *commandable) SMove(source, destination, member string) *redis.BoolCmd {
 	cmd := redis.NewBoolCmd("SMOVE", source, destination, member)
 	c.Process(HashSlot(source), cmd)
 	return cmd
 }
 
-func (c *Client) SPop(key string) *redis.StringCmd {
+func (c *commandable) SPop(key string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("SPOP", key)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SRandMember(key string) *redis.StringCmd {
+func (c *commandable) SRandMember(key string) *redis.StringCmd {
 	cmd := redis.NewStringCmd("SRANDMEMBER", key)
-	c.Process(HashSlot(key), cmd)
+	c.ProcessReadOnly(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SRem(key string, members ...string) *redis.IntCmd {
+func (c *commandable) SRem(key string, members ...string) *redis.IntCmd {
 	args := append([]string{"SREM", key}, members...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(key), cmd)
 	return cmd
 }
 
-func (c *Client) SUnion(keys ...string) *redis.StringSliceCmd {
+func (c *commandable) SUnion(keys ...string) *redis.StringSliceCmd {
 	args := append([]string{"SUNION"}, keys...)
 	cmd := redis.NewStringSliceCmd(args...)
-	c.Process(HashSlot(firstKey(keys)), cmd)
+	c.ProcessReadOnly(HashSlot(firstKey(keys)), cmd)
 	return cmd
 }
 
-func (c *Client) SUnionStore(destination string, keys ...string) *redis.IntCmd {
+func (c *commandable) SUnionStore(destination string, keys ...string) *redis.IntCmd {
 	args := append([]string{"SUNIONSTORE", destination}, keys...)
 	cmd := redis.NewIntCmd(args...)
 	c.Process(HashSlot(destination), cmd)
@@ -515,3 +533,249 @@ func (c *Client) SUnionStore(destination string, keys ...string) *redis.IntCmd {
 }
 
 //------------------------------------------------------------------------------
+
+func (c *commandable) ZAdd(key string, members ...redis.Z) *redis.IntCmd {
+	args := []string{"ZADD", key}
+	for _, m := range members {
+		args = append(args, formatFloat(m.Score), m.Member)
+	}
+	cmd := redis.NewIntCmd(args...)
+	c.Process(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZCard(key string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("ZCARD", key)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZCount(key, min, max string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("ZCOUNT", key, min, max)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZIncrBy(key string, increment float64, member string) *redis.FloatCmd {
+	cmd := redis.NewFloatCmd("ZINCRBY", key, formatFloat(increment), member)
+	c.Process(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZInterStore(destination string, store redis.ZStore, keys ...string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(zStoreArgs("ZINTERSTORE", destination, store, keys)...)
+	c.Process(HashSlot(destination), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRange(key string, start, stop int64) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(
+		"ZRANGE",
+		key,
+		strconv.FormatInt(start, 10),
+		strconv.FormatInt(stop, 10),
+	)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRangeWithScores(key string, start, stop int64) *redis.ZSliceCmd {
+	cmd := redis.NewZSliceCmd(
+		"ZRANGE",
+		key,
+		strconv.FormatInt(start, 10),
+		strconv.FormatInt(stop, 10),
+		"WITHSCORES",
+	)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRangeByLex(key string, opt redis.ZRangeByScore) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(zRangeByArgs("ZRANGEBYLEX", key, opt, false)...)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRangeByScore(key string, opt redis.ZRangeByScore) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(zRangeByArgs("ZRANGEBYSCORE", key, opt, false)...)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRangeByScoreWithScores(key string, opt redis.ZRangeByScore) *redis.ZSliceCmd {
+	cmd := redis.NewZSliceCmd(zRangeByArgs("ZRANGEBYSCORE", key, opt, true)...)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRank(key, member string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("ZRANK", key, member)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRem(key string, members ...string) *redis.IntCmd {
+	args := append([]string{"ZREM", key}, members...)
+	cmd := redis.NewIntCmd(args...)
+	c.Process(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRemRangeByLex(key, min, max string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("ZREMRANGEBYLEX", key, min, max)
+	c.Process(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRemRangeByRank(key string, start, stop int64) *redis.IntCmd {
+	cmd := redis.NewIntCmd(
+		"ZREMRANGEBYRANK",
+		key,
+		strconv.FormatInt(start, 10),
+		strconv.FormatInt(stop, 10),
+	)
+	c.Process(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRemRangeByScore(key, min, max string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("ZREMRANGEBYSCORE", key, min, max)
+	c.Process(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRevRange(key string, start, stop int64) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(
+		"ZREVRANGE",
+		key,
+		strconv.FormatInt(start, 10),
+		strconv.FormatInt(stop, 10),
+	)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRevRangeWithScores(key string, start, stop int64) *redis.ZSliceCmd {
+	cmd := redis.NewZSliceCmd(
+		"ZREVRANGE",
+		key,
+		strconv.FormatInt(start, 10),
+		strconv.FormatInt(stop, 10),
+		"WITHSCORES",
+	)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRevRangeByLex(key string, opt redis.ZRangeByScore) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(zRangeByArgs("ZREVRANGEBYLEX", key, opt, false)...)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRevRangeByScore(key string, opt redis.ZRangeByScore) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(zRangeByArgs("ZREVRANGEBYSCORE", key, opt, false)...)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRevRangeByScoreWithScores(key string, opt redis.ZRangeByScore) *redis.ZSliceCmd {
+	cmd := redis.NewZSliceCmd(zRangeByArgs("ZREVRANGEBYSCORE", key, opt, true)...)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZRevRank(key, member string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("ZREVRANK", key, member)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZScore(key, member string) *redis.FloatCmd {
+	cmd := redis.NewFloatCmd("ZSCORE", key, member)
+	c.ProcessReadOnly(HashSlot(key), cmd)
+	return cmd
+}
+
+func (c *commandable) ZUnionStore(destination string, store redis.ZStore, keys ...string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(zStoreArgs("ZUNIONSTORE", destination, store, keys)...)
+	c.Process(HashSlot(destination), cmd)
+	return cmd
+}
+
+// Builds arguments for ZRANGEBYSCORE, ZRANGEBYLEX and their ZREV* counterparts
+func zRangeByArgs(name, key string, opt redis.ZRangeByScore, withScores bool) []string {
+	args := []string{name, key, opt.Min, opt.Max}
+	if strings.HasPrefix(name, "ZREV") {
+		args[2], args[3] = opt.Max, opt.Min
+	}
+	if withScores {
+		args = append(args, "WITHSCORES")
+	}
+	if opt.Offset != 0 || opt.Count != 0 {
+		args = append(
+			args,
+			"LIMIT",
+			strconv.FormatInt(opt.Offset, 10),
+			strconv.FormatInt(opt.Count, 10),
+		)
+	}
+	return args
+}
+
+// Builds arguments for ZINTERSTORE and ZUNIONSTORE
+func zStoreArgs(name, destination string, store redis.ZStore, keys []string) []string {
+	args := []string{name, destination, strconv.FormatInt(int64(len(keys)), 10)}
+	args = append(args, keys...)
+	if len(store.Weights) > 0 {
+		args = append(args, "WEIGHTS")
+		for _, weight := range store.Weights {
+			args = append(args, strconv.FormatInt(weight, 10))
+		}
+	}
+	if store.Aggregate != "" {
+		args = append(args, "AGGREGATE", store.Aggregate)
+	}
+	return args
+}
+
+//------------------------------------------------------------------------------
+
+func (c *commandable) Eval(script string, keys []string, args []string) *redis.Cmd {
+	cmdArgs := []string{"EVAL", script, strconv.FormatInt(int64(len(keys)), 10)}
+	cmdArgs = append(cmdArgs, keys...)
+	cmdArgs = append(cmdArgs, args...)
+	cmd := redis.NewCmd(cmdArgs...)
+	c.Process(HashSlot(firstKey(keys)), cmd)
+	return cmd
+}
+
+func (c *commandable) EvalSha(sha1 string, keys []string, args []string) *redis.Cmd {
+	cmdArgs := []string{"EVALSHA", sha1, strconv.FormatInt(int64(len(keys)), 10)}
+	cmdArgs = append(cmdArgs, keys...)
+	cmdArgs = append(cmdArgs, args...)
+	cmd := redis.NewCmd(cmdArgs...)
+	c.Process(HashSlot(firstKey(keys)), cmd)
+	return cmd
+}
+
+//------------------------------------------------------------------------------
+
+// Publish posts a message to a channel. Messages are propagated to
+// all nodes, the slot of the channel only picks the receiving node
+func (c *commandable) Publish(channel, message string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("PUBLISH", channel, message)
+	c.Process(HashSlot(channel), cmd)
+	return cmd
+}
+
+// SPublish posts a message to a shard channel, served by the
+// master of the channel's slot (Redis 7+)
+func (c *commandable) SPublish(channel, message string) *redis.IntCmd {
+	cmd := redis.NewIntCmd("SPUBLISH", channel, message)
+	c.Process(HashSlot(channel), cmd)
+	return cmd
+}
+
+//------------------------------------------------------------------------------
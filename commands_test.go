@@ -0,0 +1,45 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("zRangeByArgs", func() {
+
+	It("should build arguments", func() {
+		opt := redis.ZRangeByScore{Min: "1", Max: "(5"}
+		Expect(zRangeByArgs("ZRANGEBYSCORE", "key", opt, false)).To(Equal([]string{
+			"ZRANGEBYSCORE", "key", "1", "(5",
+		}))
+
+		opt.Offset, opt.Count = 10, 20
+		Expect(zRangeByArgs("ZRANGEBYSCORE", "key", opt, true)).To(Equal([]string{
+			"ZRANGEBYSCORE", "key", "1", "(5", "WITHSCORES", "LIMIT", "10", "20",
+		}))
+	})
+
+	It("should reverse ranges", func() {
+		opt := redis.ZRangeByScore{Min: "[a", Max: "[z"}
+		Expect(zRangeByArgs("ZREVRANGEBYLEX", "key", opt, false)).To(Equal([]string{
+			"ZREVRANGEBYLEX", "key", "[z", "[a",
+		}))
+	})
+
+})
+
+var _ = Describe("zStoreArgs", func() {
+
+	It("should build arguments", func() {
+		Expect(zStoreArgs("ZUNIONSTORE", "{k}dst", redis.ZStore{}, []string{"{k}a", "{k}b"})).To(Equal([]string{
+			"ZUNIONSTORE", "{k}dst", "2", "{k}a", "{k}b",
+		}))
+
+		store := redis.ZStore{Weights: []int64{2, 3}, Aggregate: "MAX"}
+		Expect(zStoreArgs("ZINTERSTORE", "{k}dst", store, []string{"{k}a", "{k}b"})).To(Equal([]string{
+			"ZINTERSTORE", "{k}dst", "2", "{k}a", "{k}b", "WEIGHTS", "2", "3", "AGGREGATE", "MAX",
+		}))
+	})
+
+})
@@ -0,0 +1,168 @@
+package cluster
+
+import "gopkg.in/redis.v2"
+
+var errCrossSlotMSetNX = CrossSlotError("CROSSSLOT MSETNX keys must hash to the same slot")
+
+// Del deletes keys. Keys are split by hash slot and deleted in parallel,
+// one DEL per slot, pipelined per node. The reply is the total number of
+// deleted keys. Deletes are not atomic across slots, on errors some keys
+// may have been deleted.
+func (c *Client) Del(keys ...string) *redis.IntCmd {
+	slots := groupBySlot(keys)
+	if len(slots) < 2 {
+		return c.commandable.Del(keys...)
+	}
+
+	pipe := c.Pipeline()
+	defer pipe.Close()
+
+	cmds := make([]*redis.IntCmd, 0, len(slots))
+	for _, pos := range slots {
+		cmds = append(cmds, pipe.Del(pickKeys(keys, pos)...))
+	}
+
+	cmd := redis.NewIntCmd(append([]string{"DEL"}, keys...)...)
+	if _, err := pipe.Exec(); err != nil {
+		setCmdErr(cmd, err)
+		return cmd
+	}
+
+	var n int64
+	for _, del := range cmds {
+		n += del.Val()
+	}
+	setCmdVal(cmd, n)
+	return cmd
+}
+
+// MGet returns the values of all keys. Keys are split by hash slot and
+// fetched in parallel, one MGET per slot, pipelined per node. Values are
+// returned in the original order of keys.
+func (c *Client) MGet(keys ...string) *redis.SliceCmd {
+	slots := groupBySlot(keys)
+	if len(slots) < 2 {
+		return c.commandable.MGet(keys...)
+	}
+
+	cmd := redis.NewSliceCmd(append([]string{"MGET"}, keys...)...)
+	if vals, err := c.mget(keys, slots); err != nil {
+		setCmdErr(cmd, err)
+	} else {
+		setCmdVal(cmd, vals)
+	}
+	return cmd
+}
+
+// MGetMap returns the values of all existing keys, mapped by key.
+// Keys are split by hash slot, just like with MGet.
+func (c *Client) MGetMap(keys ...string) *redis.StringStringMapCmd {
+	cmd := redis.NewStringStringMapCmd(append([]string{"MGET"}, keys...)...)
+	vals, err := c.mget(keys, groupBySlot(keys))
+	if err != nil {
+		setCmdErr(cmd, err)
+		return cmd
+	}
+
+	pairs := make([]string, 0, 2*len(keys))
+	for i, val := range vals {
+		if s, ok := val.(string); ok {
+			pairs = append(pairs, keys[i], s)
+		}
+	}
+	setCmdVal(cmd, pairs)
+	return cmd
+}
+
+// MSet sets multiple key/value pairs. Pairs are split by the hash slot
+// of their keys and set in parallel, one MSET per slot, pipelined per node.
+// MSet is not atomic across slots, on errors some pairs may have been set.
+func (c *Client) MSet(pairs ...string) *redis.StatusCmd {
+	keys := pairKeys(pairs)
+	slots := groupBySlot(keys)
+	if len(slots) < 2 || len(pairs)%2 != 0 {
+		return c.commandable.MSet(pairs...)
+	}
+
+	pipe := c.Pipeline()
+	defer pipe.Close()
+
+	for _, pos := range slots {
+		args := make([]string, 0, 2*len(pos))
+		for _, n := range pos {
+			args = append(args, pairs[2*n], pairs[2*n+1])
+		}
+		pipe.MSet(args...)
+	}
+
+	cmd := redis.NewStatusCmd(append([]string{"MSET"}, pairs...)...)
+	if _, err := pipe.Exec(); err != nil {
+		setCmdErr(cmd, err)
+	} else {
+		setCmdVal(cmd, "OK")
+	}
+	return cmd
+}
+
+// MSetNX sets multiple key/value pairs, only if none of the keys exist.
+// To remain atomic, all keys must hash to the same slot, cross-slot calls
+// are refused without contacting the cluster.
+func (c *Client) MSetNX(pairs ...string) *redis.BoolCmd {
+	if len(groupBySlot(pairKeys(pairs))) < 2 {
+		return c.commandable.MSetNX(pairs...)
+	}
+
+	cmd := redis.NewBoolCmd(append([]string{"MSETNX"}, pairs...)...)
+	setCmdErr(cmd, errCrossSlotMSetNX)
+	return cmd
+}
+
+// Fetches values of keys grouped by slot, returns them in the order of keys
+func (c *Client) mget(keys []string, slots map[int][]int) ([]interface{}, error) {
+	pipe := c.Pipeline()
+	defer pipe.Close()
+
+	cmds := make(map[int]*redis.SliceCmd, len(slots))
+	for slot, pos := range slots {
+		cmds[slot] = pipe.MGet(pickKeys(keys, pos)...)
+	}
+	if _, err := pipe.Exec(); err != nil {
+		return nil, err
+	}
+
+	vals := make([]interface{}, len(keys))
+	for slot, cmd := range cmds {
+		for i, val := range cmd.Val() {
+			vals[slots[slot][i]] = val
+		}
+	}
+	return vals, nil
+}
+
+// Groups the positions of keys by hash slot
+func groupBySlot(keys []string) map[int][]int {
+	slots := make(map[int][]int)
+	for i, key := range keys {
+		slot := HashSlot(key)
+		slots[slot] = append(slots[slot], i)
+	}
+	return slots
+}
+
+// Picks keys at positions
+func pickKeys(keys []string, pos []int) []string {
+	picked := make([]string, len(pos))
+	for i, n := range pos {
+		picked[i] = keys[n]
+	}
+	return picked
+}
+
+// Extracts keys from key/value pairs
+func pairKeys(pairs []string) []string {
+	keys := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		keys = append(keys, pairs[i])
+	}
+	return keys
+}
@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("groupBySlot", func() {
+
+	It("should group key positions", func() {
+		keys := []string{"{a}1", "{b}1", "{a}2", "{b}2", "{c}"}
+		slots := groupBySlot(keys)
+		Expect(slots).To(HaveLen(3))
+		Expect(slots[HashSlot("a")]).To(Equal([]int{0, 2}))
+		Expect(slots[HashSlot("b")]).To(Equal([]int{1, 3}))
+		Expect(slots[HashSlot("c")]).To(Equal([]int{4}))
+		Expect(pickKeys(keys, slots[HashSlot("b")])).To(Equal([]string{"{b}1", "{b}2"}))
+	})
+
+})
+
+var _ = Describe("pairKeys", func() {
+
+	It("should extract keys", func() {
+		Expect(pairKeys([]string{"a", "1", "b", "2"})).To(Equal([]string{"a", "b"}))
+		Expect(pairKeys([]string{"a", "1", "b"})).To(Equal([]string{"a", "b"}))
+		Expect(pairKeys(nil)).To(BeEmpty())
+	})
+
+})
+
+var _ = Describe("Client", func() {
+
+	It("should refuse cross-slot MSETNX", func() {
+		client := newClient(&Options{})
+		defer client.Close()
+
+		Expect(client.MSetNX("{a}1", "x", "{b}1", "y").Err()).To(Equal(errCrossSlotMSetNX))
+	})
+
+})
+
+var _ = Describe("Cross-slot commands", func() {
+	fake := newFakeCluster()
+
+	// Keys {bar}* hash to slot 5061, {foo}* to slot 12182
+	var keys = []string{"{bar}1", "{foo}1", "{bar}2", "{foo}2"}
+
+	var serve = func(node *fakeNode, cmd []string, reply string) {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(node.cmds).Should(Receive(Equal(cmd)))
+			node.Reply(reply)
+		}()
+	}
+
+	It("should sum DEL counts across slots", func() {
+		serve(fake.nodes[0], []string{"DEL", "{bar}1", "{bar}2"}, ":1\r\n")
+		serve(fake.nodes[1], []string{"DEL", "{foo}1", "{foo}2"}, ":2\r\n")
+
+		cmd := fake.client.Del(keys...)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal(int64(3)))
+	})
+
+	It("should set MSET status", func() {
+		serve(fake.nodes[0], []string{"MSET", "{bar}1", "a"}, "+OK\r\n")
+		serve(fake.nodes[1], []string{"MSET", "{foo}1", "b"}, "+OK\r\n")
+		Expect(fake.client.MSet("{bar}1", "a", "{foo}1", "b").Result()).To(Equal("OK"))
+
+		serve(fake.nodes[0], []string{"MSET", "{bar}1", "a"}, "+OK\r\n")
+		serve(fake.nodes[1], []string{"MSET", "{foo}1", "b"}, "-OOM command not allowed when used memory > 'maxmemory'\r\n")
+		Expect(fake.client.MSet("{bar}1", "a", "{foo}1", "b").Err()).To(MatchError(ContainSubstring("OOM")))
+	})
+
+	It("should refuse cross-slot MSETNX without sending it", func() {
+		err := fake.client.MSetNX("{bar}1", "a", "{foo}1", "b").Err()
+		Expect(err).To(BeAssignableToTypeOf(CrossSlotError("")))
+		Consistently(fake.nodes[0].cmds, "50ms").ShouldNot(Receive())
+		Consistently(fake.nodes[1].cmds, "50ms").ShouldNot(Receive())
+	})
+
+	It("should return MGET values in the order of keys", func() {
+		serve(fake.nodes[0], []string{"MGET", "{bar}1", "{bar}2"}, "*2\r\n$1\r\na\r\n$-1\r\n")
+		serve(fake.nodes[1], []string{"MGET", "{foo}1", "{foo}2"}, "*2\r\n$1\r\nb\r\n$1\r\nc\r\n")
+
+		cmd := fake.client.MGet(keys...)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal([]interface{}{"a", "b", nil, "c"}))
+	})
+
+	It("should map MGET values by key", func() {
+		serve(fake.nodes[0], []string{"MGET", "{bar}1", "{bar}2"}, "*2\r\n$1\r\na\r\n$-1\r\n")
+		serve(fake.nodes[1], []string{"MGET", "{foo}1", "{foo}2"}, "*2\r\n$1\r\nb\r\n$1\r\nc\r\n")
+
+		cmd := fake.client.MGetMap(keys...)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal(map[string]string{"{bar}1": "a", "{foo}1": "b", "{foo}2": "c"}))
+	})
+
+	It("should propagate MGET errors of a single slot", func() {
+		serve(fake.nodes[0], []string{"MGET", "{bar}1", "{bar}2"}, "*2\r\n$1\r\na\r\n$-1\r\n")
+		serve(fake.nodes[1], []string{"MGET", "{foo}1", "{foo}2"}, "-ERR boom\r\n")
+		Expect(fake.client.MGet(keys...).Err()).To(MatchError(ContainSubstring("ERR boom")))
+
+		serve(fake.nodes[0], []string{"MGET", "{bar}1", "{bar}2"}, "*2\r\n$1\r\na\r\n$-1\r\n")
+		serve(fake.nodes[1], []string{"MGET", "{foo}1", "{foo}2"}, "-ERR boom\r\n")
+		Expect(fake.client.MGetMap(keys...).Err()).To(MatchError(ContainSubstring("ERR boom")))
+	})
+
+})
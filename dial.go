@@ -0,0 +1,159 @@
+package cluster
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dials a node and performs the connection handshake
+func (o *Options) dial(addr string) (net.Conn, error) {
+	cn, err := net.DialTimeout("tcp", addr, o.dialTimeout())
+	if err != nil {
+		return nil, err
+	}
+	if o.TLSConfig != nil {
+		cn = tls.Client(cn, o.tlsConfig(addr))
+	}
+
+	if err := o.handshake(cn, addr); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// Completes the TLS handshake, authenticates a new connection
+// and enables replica reads
+func (o *Options) handshake(cn net.Conn, addr string) error {
+	if err := cn.SetDeadline(time.Now().Add(o.dialTimeout())); err != nil {
+		return err
+	}
+
+	if tc, ok := cn.(*tls.Conn); ok {
+		if err := tc.Handshake(); err != nil {
+			return err
+		}
+	}
+
+	username, password, err := o.credentials(addr)
+	if err != nil {
+		return err
+	}
+	if username != "" {
+		if err := handshakeCmd(cn, "AUTH", username, password); err != nil {
+			return err
+		}
+	} else if password != "" {
+		if err := handshakeCmd(cn, "AUTH", password); err != nil {
+			return err
+		}
+	}
+	if o.readReplicas() {
+		if err := handshakeCmd(cn, "READONLY"); err != nil {
+			return err
+		}
+	}
+	return cn.SetDeadline(time.Time{})
+}
+
+// Returns the TLS config for a node, the server name defaults
+// to the host of the address
+func (o *Options) tlsConfig(addr string) *tls.Config {
+	cfg := o.TLSConfig.Clone()
+	if cfg.ServerName == "" {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			cfg.ServerName = host
+		}
+	}
+	return cfg
+}
+
+// Sends a single command over a raw connection,
+// expects a status reply
+func handshakeCmd(cn net.Conn, args ...string) error {
+	if err := writeCmd(cn, args...); err != nil {
+		return err
+	}
+
+	line, err := bufio.NewReader(cn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\r\n")
+
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return nil
+	case strings.HasPrefix(line, "-"):
+		return errors.New(line[1:])
+	}
+	return errors.New("redis cluster: unexpected reply " + strconv.Quote(line))
+}
+
+// Writes a single command to a raw connection
+func writeCmd(cn net.Conn, args ...string) error {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	_, err := cn.Write(buf)
+	return err
+}
+
+// Reads a single reply from a raw connection. Error replies
+// are returned as values, nil replies as nil
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis cluster: unexpected reply \"\"")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return errors.New(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*', '>':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		vals := make([]interface{}, n)
+		for i := range vals {
+			if vals[i], err = readReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return vals, nil
+	}
+	return nil, errors.New("redis cluster: unexpected reply " + strconv.Quote(line))
+}
@@ -0,0 +1,89 @@
+package cluster
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("handshake", func() {
+	var client, server net.Conn
+
+	// Expects a raw request and responds with a reply
+	var expect = func(pairs ...string) {
+		go func() {
+			defer GinkgoRecover()
+
+			for i := 0; i < len(pairs); i += 2 {
+				buf := make([]byte, len(pairs[i]))
+				_, err := io.ReadFull(server, buf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(buf)).To(Equal(pairs[i]))
+				server.Write([]byte(pairs[i+1]))
+			}
+		}()
+	}
+
+	BeforeEach(func() {
+		client, server = net.Pipe()
+	})
+
+	AfterEach(func() {
+		client.Close()
+		server.Close()
+	})
+
+	It("should send raw commands", func() {
+		expect("*1\r\n$8\r\nREADONLY\r\n", "+OK\r\n")
+		Expect(handshakeCmd(client, "READONLY")).To(Succeed())
+	})
+
+	It("should return error replies", func() {
+		expect("*2\r\n$4\r\nAUTH\r\n$3\r\nbad\r\n", "-ERR invalid password\r\n")
+		Expect(handshakeCmd(client, "AUTH", "bad")).To(MatchError("ERR invalid password"))
+	})
+
+	It("should authenticate before enabling replica reads", func() {
+		opts := &Options{Password: "secret", ReadMode: ReadRandomReplica}
+		expect(
+			"*2\r\n$4\r\nAUTH\r\n$6\r\nsecret\r\n", "+OK\r\n",
+			"*1\r\n$8\r\nREADONLY\r\n", "+OK\r\n",
+		)
+		Expect(opts.handshake(client, "127.0.0.1:7000")).To(Succeed())
+	})
+
+	It("should authenticate ACL users", func() {
+		opts := &Options{Username: "app", Password: "secret"}
+		expect("*3\r\n$4\r\nAUTH\r\n$3\r\napp\r\n$6\r\nsecret\r\n", "+OK\r\n")
+		Expect(opts.handshake(client, "127.0.0.1:7000")).To(Succeed())
+	})
+
+	It("should ask the credentials provider", func() {
+		var addrs []string
+		opts := &Options{Password: "ignored", Credentials: func(addr string) (string, string, error) {
+			addrs = append(addrs, addr)
+			return "app", "token", nil
+		}}
+		expect("*3\r\n$4\r\nAUTH\r\n$3\r\napp\r\n$5\r\ntoken\r\n", "+OK\r\n")
+		Expect(opts.handshake(client, "127.0.0.1:7000")).To(Succeed())
+		Expect(addrs).To(Equal([]string{"127.0.0.1:7000"}))
+
+		opts.Credentials = func(string) (string, string, error) { return "", "", errors.New("expired") }
+		Expect(opts.handshake(client, "127.0.0.1:7000")).To(MatchError("expired"))
+	})
+
+	It("should read replies", func() {
+		rd := bufio.NewReader(strings.NewReader("*4\r\n$8\r\nsmessage\r\n$-1\r\n:3\r\n+OK\r\n-MOVED 1 127.0.0.1:7001\r\n"))
+		Expect(readReply(rd)).To(Equal([]interface{}{"smessage", nil, int64(3), "OK"}))
+		Expect(readReply(rd)).To(MatchError("MOVED 1 127.0.0.1:7001"))
+
+		_, err := readReply(bufio.NewReader(strings.NewReader("?\r\n")))
+		Expect(err).To(MatchError(`redis cluster: unexpected reply "?"`))
+	})
+
+})
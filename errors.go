@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"gopkg.in/redis.v2"
+)
+
+// MovedError is returned when a slot is served by another node
+type MovedError struct {
+	Slot int
+	Addr string
+}
+
+func (e *MovedError) Error() string {
+	return "MOVED " + strconv.Itoa(e.Slot) + " " + e.Addr
+}
+
+// AskError is returned when a slot is being migrated and
+// the key must be requested from another node
+type AskError struct {
+	Slot int
+	Addr string
+}
+
+func (e *AskError) Error() string {
+	return "ASK " + strconv.Itoa(e.Slot) + " " + e.Addr
+}
+
+// TryAgainError is returned when a multi-key command cannot be
+// served during resharding
+type TryAgainError string
+
+func (e TryAgainError) Error() string { return string(e) }
+
+// ClusterDownError is returned when the cluster is unable to serve
+// a slot, e.g. because it is not covered by any node
+type ClusterDownError string
+
+func (e ClusterDownError) Error() string { return string(e) }
+
+// CrossSlotError is returned when the keys of a command
+// hash to different slots
+type CrossSlotError string
+
+func (e CrossSlotError) Error() string { return string(e) }
+
+// NodeError wraps the final error of a command, together with
+// the address of the node that returned it
+type NodeError struct {
+	Addr string
+	Err  error
+}
+
+func (e *NodeError) Error() string {
+	return e.Err.Error() + " (node " + e.Addr + ")"
+}
+
+// Unwrap returns the wrapped error
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// Converts cluster error replies into typed errors,
+// returns all other errors unchanged
+func parseError(err error) error {
+	msg := err.Error()
+	name := msg
+	if pos := strings.IndexByte(msg, ' '); pos > -1 {
+		name = msg[:pos]
+	}
+
+	switch name {
+	case "MOVED", "ASK":
+		parts := strings.SplitN(msg, " ", 3)
+		if len(parts) != 3 {
+			return err
+		}
+		slot, perr := strconv.Atoi(parts[1])
+		if perr != nil {
+			return err
+		}
+		if name == "MOVED" {
+			return &MovedError{Slot: slot, Addr: parts[2]}
+		}
+		return &AskError{Slot: slot, Addr: parts[2]}
+	case "TRYAGAIN":
+		return TryAgainError(msg)
+	case "CLUSTERDOWN":
+		return ClusterDownError(msg)
+	case "CROSSSLOT":
+		return CrossSlotError(msg)
+	}
+	return err
+}
+
+// Is err a transient error, which may succeed when retried
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	} else if isConnError(err) {
+		return true
+	}
+
+	switch parseError(err).(type) {
+	case TryAgainError, ClusterDownError:
+		return true
+	}
+	return false
+}
+
+// Is err a TRYAGAIN error
+func isTryAgain(err error) bool {
+	_, ok := parseError(err).(TryAgainError)
+	return ok
+}
+
+// Wraps the error of a failed command with the address of the node
+func wrapCmdErr(cmd redis.Cmder, addr string) {
+	if err := cmd.Err(); err != nil && err != redis.Nil && err != context.Canceled && err != context.DeadlineExceeded {
+		setCmdErr(cmd, &NodeError{Addr: addr, Err: parseError(err)})
+	}
+}
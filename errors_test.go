@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("parseError", func() {
+
+	It("should parse redirects", func() {
+		Expect(parseError(errors.New("MOVED 3999 127.0.0.1:6381"))).To(Equal(&MovedError{Slot: 3999, Addr: "127.0.0.1:6381"}))
+		Expect(parseError(errors.New("ASK 3999 127.0.0.1:6381"))).To(Equal(&AskError{Slot: 3999, Addr: "127.0.0.1:6381"}))
+		Expect(parseError(errors.New("MOVED 3999 127.0.0.1:6381")).Error()).To(Equal("MOVED 3999 127.0.0.1:6381"))
+	})
+
+	It("should parse cluster errors", func() {
+		Expect(parseError(errors.New("TRYAGAIN Multiple keys request during rehashing of slot"))).To(BeAssignableToTypeOf(TryAgainError("")))
+		Expect(parseError(errors.New("CLUSTERDOWN The cluster is down"))).To(Equal(ClusterDownError("CLUSTERDOWN The cluster is down")))
+		Expect(parseError(errors.New("CROSSSLOT Keys in request don't hash to the same slot"))).To(BeAssignableToTypeOf(CrossSlotError("")))
+	})
+
+	It("should leave other errors unchanged", func() {
+		err := errors.New("ERR unknown command 'FOO'")
+		Expect(parseError(err)).To(Equal(err))
+		err = errors.New("MOVED invalid")
+		Expect(parseError(err)).To(Equal(err))
+	})
+
+})
+
+var _ = Describe("isRetryable", func() {
+
+	It("should detect transient errors", func() {
+		Expect(isRetryable(nil)).To(BeFalse())
+		Expect(isRetryable(io.EOF)).To(BeTrue())
+		Expect(isRetryable(errors.New("TRYAGAIN Multiple keys request during rehashing of slot"))).To(BeTrue())
+		Expect(isRetryable(errors.New("CLUSTERDOWN The cluster is down"))).To(BeTrue())
+		Expect(isRetryable(errors.New("MOVED 3999 127.0.0.1:6381"))).To(BeFalse())
+		Expect(isRetryable(errors.New("ERR unknown command 'FOO'"))).To(BeFalse())
+		Expect(isRetryable(redis.Nil)).To(BeFalse())
+	})
+
+})
+
+var _ = Describe("NodeError", func() {
+
+	It("should wrap errors", func() {
+		err := &NodeError{Addr: "127.0.0.1:7000", Err: ClusterDownError("CLUSTERDOWN The cluster is down")}
+		Expect(err.Error()).To(Equal("CLUSTERDOWN The cluster is down (node 127.0.0.1:7000)"))
+		Expect(err.Unwrap()).To(Equal(ClusterDownError("CLUSTERDOWN The cluster is down")))
+	})
+
+	It("should wrap command errors", func() {
+		cmd := redis.NewStatusCmd("PING")
+		setCmdErr(cmd, errors.New("CLUSTERDOWN The cluster is down"))
+		wrapCmdErr(cmd, "127.0.0.1:7000")
+		Expect(cmd.Err()).To(Equal(&NodeError{Addr: "127.0.0.1:7000", Err: ClusterDownError("CLUSTERDOWN The cluster is down")}))
+
+		cmd = redis.NewStatusCmd("GET")
+		setCmdErr(cmd, redis.Nil)
+		wrapCmdErr(cmd, "127.0.0.1:7000")
+		Expect(cmd.Err()).To(Equal(redis.Nil))
+	})
+
+})
@@ -0,0 +1,57 @@
+package cluster
+
+import "sort"
+
+// TopologyEvent describes the changes between two versions of the
+// slot cache, as seen by the client
+type TopologyEvent struct {
+	// Slot ranges served by a new master, with their new addresses
+	Moved []SlotRange
+	// Nodes that started serving slots
+	Added []string
+	// Nodes that stopped serving slots
+	Removed []string
+	// Replicas that were promoted to masters
+	Promoted []string
+}
+
+// Compares two topologies, returns nil if their slot tables are equal
+func newTopologyEvent(prev, next *topology) *TopologyEvent {
+	if equalSlots(prev.slots, next.slots) {
+		return nil
+	}
+
+	event := &TopologyEvent{
+		Moved: slotRanges(next.slots, func(slot int) bool {
+			return prev.slotAddr(slot) != next.slotAddr(slot)
+		}),
+	}
+
+	prevNodes, nextNodes := prev.nodes(), next.nodes()
+	for addr := range nextNodes {
+		if _, ok := prevNodes[addr]; !ok {
+			event.Added = append(event.Added, addr)
+		}
+	}
+	for addr := range prevNodes {
+		if _, ok := nextNodes[addr]; !ok {
+			event.Removed = append(event.Removed, addr)
+		}
+	}
+
+	prevMasters := make(map[string]struct{})
+	for _, addr := range prev.masterAddrs() {
+		prevMasters[addr] = struct{}{}
+	}
+	for _, addr := range next.masterAddrs() {
+		_, known := prevNodes[addr]
+		if _, master := prevMasters[addr]; known && !master {
+			event.Promoted = append(event.Promoted, addr)
+		}
+	}
+
+	sort.Strings(event.Added)
+	sort.Strings(event.Removed)
+	sort.Strings(event.Promoted)
+	return event
+}
@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TopologyEvent", func() {
+	var prev *topology
+
+	BeforeEach(func() {
+		prev = newTopology(nil).withSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7002"}},
+			{min: 8192, max: 16383, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7003"}},
+		})
+	})
+
+	It("should not report equal topologies", func() {
+		next := prev.withSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7002"}},
+			{min: 8192, max: 16383, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7003"}},
+		})
+		Expect(newTopologyEvent(prev, next)).To(BeNil())
+	})
+
+	It("should report failovers", func() {
+		next := prev.withSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7002"}},
+			{min: 8192, max: 16383, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7003"}},
+		})
+		Expect(newTopologyEvent(prev, next)).To(Equal(&TopologyEvent{
+			Moved:    []SlotRange{{Min: 0, Max: 8191, Master: "127.0.0.1:7002", Replicas: []string{}}},
+			Removed:  []string{"127.0.0.1:7000"},
+			Promoted: []string{"127.0.0.1:7002"},
+		}))
+	})
+
+	It("should report migrated slots and new nodes", func() {
+		next := prev.withSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7002"}},
+			{min: 8192, max: 12287, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7003"}},
+			{min: 12288, max: 16383, addrs: []string{"127.0.0.1:7004", "127.0.0.1:7005"}},
+		})
+		Expect(newTopologyEvent(prev, next)).To(Equal(&TopologyEvent{
+			Moved: []SlotRange{{Min: 12288, Max: 16383, Master: "127.0.0.1:7004", Replicas: []string{"127.0.0.1:7005"}}},
+			Added: []string{"127.0.0.1:7004", "127.0.0.1:7005"},
+		}))
+	})
+
+	It("should be returned by the slot cache", func() {
+		client := newClient(&Options{OnTopologyChange: func(*TopologyEvent) {}})
+		defer client.Close()
+
+		Expect(client.cacheSlots([]slotInfo{{min: 0, max: 16383, addrs: []string{"127.0.0.1:7000"}}})).To(BeNil())
+		event := client.cacheSlots([]slotInfo{{min: 0, max: 16383, addrs: []string{"127.0.0.1:7001"}}})
+		Expect(event).NotTo(BeNil())
+		Expect(event.Added).To(Equal([]string{"127.0.0.1:7001"}))
+		Expect(event.Removed).To(Equal([]string{"127.0.0.1:7000"}))
+	})
+
+})
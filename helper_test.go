@@ -0,0 +1,156 @@
+package cluster
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeCluster is a client connected to two fake nodes
+type fakeCluster struct {
+	client *Client
+	nodes  []*fakeNode
+}
+
+// Starts two fake nodes and a client before each spec of the container,
+// closes them after. Slots 0-8191 are served by the first, slots
+// 8192-16383 by the second node.
+func newFakeCluster() *fakeCluster {
+	fake := new(fakeCluster)
+
+	BeforeEach(func() {
+		fake.nodes = []*fakeNode{newFakeNode(), newFakeNode()}
+		fake.client = newClient(&Options{})
+		fake.route(fake.nodes[0], fake.nodes[1])
+	})
+
+	AfterEach(func() {
+		fake.client.Close()
+		for _, node := range fake.nodes {
+			node.Close()
+		}
+	})
+
+	return fake
+}
+
+// Serves slots 0-8191 by the first, slots 8192-16383 by the second node
+func (f *fakeCluster) route(first, second *fakeNode) {
+	f.client.lock.Lock()
+	defer f.client.lock.Unlock()
+
+	f.client.cacheSlots([]slotInfo{
+		{min: 0, max: 8191, addrs: []string{first.Addr()}},
+		{min: 8192, max: 16383, addrs: []string{second.Addr()}},
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// fakeNode accepts connections and records the commands it receives,
+// except CLUSTER commands, which are refused, and UNWATCH. Replies are
+// written to the connection that sent the latest command.
+type fakeNode struct {
+	ln   net.Listener
+	cmds chan []string
+
+	mu     sync.Mutex
+	conns  []net.Conn
+	latest net.Conn
+}
+
+func newFakeNode() *fakeNode {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+
+	node := &fakeNode{ln: ln, cmds: make(chan []string, 100)}
+	go node.serve()
+	return node
+}
+
+func (n *fakeNode) Addr() string { return n.ln.Addr().String() }
+
+func (n *fakeNode) Close() {
+	n.ln.Close()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, cn := range n.conns {
+		cn.Close()
+	}
+}
+
+// Writes a raw reply to the connection that sent the latest command
+func (n *fakeNode) Reply(raw string) {
+	var cn net.Conn
+	Eventually(func() net.Conn {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		cn = n.latest
+		return cn
+	}).ShouldNot(BeNil())
+	_, _ = cn.Write([]byte(raw))
+}
+
+func (n *fakeNode) serve() {
+	for {
+		cn, err := n.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		n.mu.Lock()
+		n.conns = append(n.conns, cn)
+		n.mu.Unlock()
+
+		go func() {
+			rd := bufio.NewReader(cn)
+			for {
+				args, err := readFakeCommand(rd)
+				if err != nil {
+					return
+				}
+				switch args[0] {
+				case "CLUSTER":
+					_, _ = cn.Write([]byte("-ERR This instance has cluster support disabled\r\n"))
+					continue
+				case "UNWATCH":
+					_, _ = cn.Write([]byte("+OK\r\n"))
+					continue
+				}
+
+				n.mu.Lock()
+				n.latest = cn
+				n.mu.Unlock()
+				n.cmds <- args
+			}
+		}()
+	}
+}
+
+// Reads a single command, sent as a RESP array of bulk strings
+func readFakeCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, n)
+	for i := range args {
+		if _, err := rd.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimRight(arg, "\r\n")
+	}
+	return args, nil
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"net"
 	"strconv"
+	"strings"
+
+	"gopkg.in/redis.v2"
 )
 
 type slotInfo struct {
@@ -55,3 +58,165 @@ func parseSlotInfo(res []interface{}) ([]slotInfo, error) {
 	}
 	return infos, nil
 }
+
+//------------------------------------------------------------------------------
+
+// NodeInfo describes a cluster node, as reported by CLUSTER NODES
+type NodeInfo struct {
+	ID   string
+	Addr string // host:port, without the cluster bus port
+
+	// Flags, e.g. myself, master, slave, fail?, fail, handshake, noaddr
+	Flags []string
+	// ID of the master, for replicas
+	MasterID string
+
+	// Unix times in milliseconds of the last ping sent
+	// and the last pong received
+	PingSent, PongRecv int64
+	ConfigEpoch        int64
+	Connected          bool
+
+	// Served slot ranges, min and max inclusive
+	Slots [][2]int
+	// Slots being imported from, or migrated to, other nodes by node ID
+	Importing, Migrating map[int]string
+}
+
+// HasFlag checks if the node has a flag
+func (n *NodeInfo) HasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMaster returns true for masters
+func (n *NodeInfo) IsMaster() bool { return n.HasFlag("master") }
+
+// IsReplica returns true for replicas
+func (n *NodeInfo) IsReplica() bool { return n.HasFlag("slave") }
+
+// IsFailed returns true for nodes that are marked as failing,
+// by the majority of masters (fail) or by the reporting node (pfail)
+func (n *NodeInfo) IsFailed() bool { return n.HasFlag("fail") || n.HasFlag("fail?") }
+
+// ClusterNodes returns the nodes of the cluster, as seen by the
+// first reachable node
+func (c *Client) ClusterNodes() ([]NodeInfo, error) {
+	err := errNoAddresses
+	for _, addr := range c.topo().addrs {
+		if err = c.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cmd := redis.NewStringCmd("CLUSTER", "NODES")
+		c.processAddr(addr, cmd)
+		if err = cmd.Err(); err == nil {
+			return parseNodeInfo(cmd.Val())
+		} else if !isConnError(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+var errInvalidNodeInfo = errors.New("redis-cluster: invalid node info")
+
+func parseNodeInfo(res string) ([]NodeInfo, error) {
+	var infos []NodeInfo
+	for _, line := range strings.Split(res, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		} else if len(fields) < 8 {
+			return nil, errInvalidNodeInfo
+		}
+
+		info := NodeInfo{
+			ID:        fields[0],
+			Addr:      fields[1],
+			Flags:     strings.Split(fields[2], ","),
+			Connected: fields[7] == "connected",
+		}
+		if n := strings.IndexAny(info.Addr, "@,"); n > -1 {
+			info.Addr = info.Addr[:n]
+		}
+		if fields[3] != "-" {
+			info.MasterID = fields[3]
+		}
+
+		var err error
+		if info.PingSent, err = strconv.ParseInt(fields[4], 10, 64); err != nil {
+			return nil, errInvalidNodeInfo
+		}
+		if info.PongRecv, err = strconv.ParseInt(fields[5], 10, 64); err != nil {
+			return nil, errInvalidNodeInfo
+		}
+		if info.ConfigEpoch, err = strconv.ParseInt(fields[6], 10, 64); err != nil {
+			return nil, errInvalidNodeInfo
+		}
+
+		for _, field := range fields[8:] {
+			if err := info.parseSlots(field); err != nil {
+				return nil, err
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Parses a slot field, i.e. "0-5460", "5461", "[5462->-<id>]"
+// (migrating) or "[5463-<-<id>]" (importing)
+func (n *NodeInfo) parseSlots(field string) error {
+	if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+		field = field[1 : len(field)-1]
+
+		marks, target := &n.Migrating, "->-"
+		pos := strings.Index(field, target)
+		if pos < 0 {
+			marks, target = &n.Importing, "-<-"
+			pos = strings.Index(field, target)
+		}
+		if pos < 0 {
+			return errInvalidNodeInfo
+		}
+
+		slot, err := parseSlot(field[:pos])
+		if err != nil {
+			return err
+		}
+		if *marks == nil {
+			*marks = make(map[int]string)
+		}
+		(*marks)[slot] = field[pos+len(target):]
+		return nil
+	}
+
+	min, max := field, field
+	if pos := strings.IndexByte(field, '-'); pos > -1 {
+		min, max = field[:pos], field[pos+1:]
+	}
+
+	lo, err := parseSlot(min)
+	if err != nil {
+		return err
+	}
+	hi, err := parseSlot(max)
+	if err != nil || hi < lo {
+		return errInvalidNodeInfo
+	}
+	n.Slots = append(n.Slots, [2]int{lo, hi})
+	return nil
+}
+
+func parseSlot(s string) (int, error) {
+	slot, err := strconv.Atoi(s)
+	if err != nil || slot < 0 || slot >= HashSlots {
+		return 0, errInvalidNodeInfo
+	}
+	return slot, nil
+}
@@ -24,3 +24,55 @@ var _ = Describe("slotInfo", func() {
 	})
 
 })
+
+var _ = Describe("NodeInfo", func() {
+
+	It("should parse from result", func() {
+		infos, err := parseNodeInfo("" +
+			"07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected\n" +
+			"67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002,node-2 master - 0 1426238316232 2 connected 5461-10922 [10923->-292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f]\n" +
+			"292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30003 myself,master - 0 0 3 connected 10924-16383 [10923-<-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1]\n" +
+			"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 master,fail? - 1426238316232 1426238315000 1 disconnected 0-5459 5460\n",
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infos).To(HaveLen(4))
+
+		Expect(infos[0]).To(Equal(NodeInfo{
+			ID:          "07c37dfeb235213a872192d90877d0cd55635b91",
+			Addr:        "127.0.0.1:30004",
+			Flags:       []string{"slave"},
+			MasterID:    "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca",
+			PongRecv:    1426238317239,
+			ConfigEpoch: 4,
+			Connected:   true,
+		}))
+		Expect(infos[0].IsReplica()).To(BeTrue())
+		Expect(infos[0].IsMaster()).To(BeFalse())
+
+		Expect(infos[1].Addr).To(Equal("127.0.0.1:30002"))
+		Expect(infos[1].Slots).To(Equal([][2]int{{5461, 10922}}))
+		Expect(infos[1].Migrating).To(Equal(map[int]string{10923: "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f"}))
+		Expect(infos[1].Importing).To(BeNil())
+
+		Expect(infos[2].Addr).To(Equal("127.0.0.1:30003"))
+		Expect(infos[2].HasFlag("myself")).To(BeTrue())
+		Expect(infos[2].Importing).To(Equal(map[int]string{10923: "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1"}))
+
+		Expect(infos[3].IsFailed()).To(BeTrue())
+		Expect(infos[3].Connected).To(BeFalse())
+		Expect(infos[3].PingSent).To(Equal(int64(1426238316232)))
+		Expect(infos[3].Slots).To(Equal([][2]int{{0, 5459}, {5460, 5460}}))
+	})
+
+	It("should reject invalid results", func() {
+		_, err := parseNodeInfo("07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004 slave")
+		Expect(err).To(Equal(errInvalidNodeInfo))
+		_, err = parseNodeInfo("07c37dfe 127.0.0.1:30004 master - 0 0 1 connected 5000-4000")
+		Expect(err).To(Equal(errInvalidNodeInfo))
+		_, err = parseNodeInfo("07c37dfe 127.0.0.1:30004 master - 0 0 1 connected [100-x]")
+		Expect(err).To(Equal(errInvalidNodeInfo))
+		_, err = parseNodeInfo("07c37dfe 127.0.0.1:30004 master - 0 0 1 connected 16384")
+		Expect(err).To(Equal(errInvalidNodeInfo))
+	})
+
+})
@@ -0,0 +1,57 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyTracker keeps a moving average of round-trip times by address
+type latencyTracker struct {
+	avgs map[string]time.Duration
+
+	sync.Mutex
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{avgs: make(map[string]time.Duration)}
+}
+
+// Observe records a round-trip time for addr
+func (t *latencyTracker) Observe(addr string, rtt time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	if avg, ok := t.avgs[addr]; ok {
+		t.avgs[addr] = avg + (rtt-avg)/5
+	} else {
+		t.avgs[addr] = rtt
+	}
+}
+
+// Get returns the average round-trip time for addr
+func (t *latencyTracker) Get(addr string) (time.Duration, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	avg, ok := t.avgs[addr]
+	return avg, ok
+}
+
+// Fastest returns the address with the lowest average round-trip time.
+// Addresses without observations are preferred, so they can be measured.
+func (t *latencyTracker) Fastest(addrs []string) string {
+	t.Lock()
+	defer t.Unlock()
+
+	fastest, min := "", time.Duration(-1)
+	for _, addr := range addrs {
+		avg, ok := t.avgs[addr]
+		if !ok {
+			return addr
+		}
+		if min < 0 || avg < min {
+			fastest, min = addr, avg
+		}
+	}
+	return fastest
+}
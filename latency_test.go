@@ -0,0 +1,45 @@
+package cluster
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("latencyTracker", func() {
+	var subject *latencyTracker
+
+	BeforeEach(func() {
+		subject = newLatencyTracker()
+	})
+
+	It("should track moving averages", func() {
+		_, ok := subject.Get("127.0.0.1:7000")
+		Expect(ok).To(BeFalse())
+
+		subject.Observe("127.0.0.1:7000", 10*time.Millisecond)
+		avg, ok := subject.Get("127.0.0.1:7000")
+		Expect(ok).To(BeTrue())
+		Expect(avg).To(Equal(10 * time.Millisecond))
+
+		subject.Observe("127.0.0.1:7000", 20*time.Millisecond)
+		avg, _ = subject.Get("127.0.0.1:7000")
+		Expect(avg).To(Equal(12 * time.Millisecond))
+	})
+
+	It("should find the fastest address", func() {
+		subject.Observe("127.0.0.1:7000", 3*time.Millisecond)
+		subject.Observe("127.0.0.1:7004", 1*time.Millisecond)
+		subject.Observe("127.0.0.1:7008", 2*time.Millisecond)
+		Expect(subject.Fastest([]string{"127.0.0.1:7000", "127.0.0.1:7004", "127.0.0.1:7008"})).To(Equal("127.0.0.1:7004"))
+		Expect(subject.Fastest([]string{"127.0.0.1:7000", "127.0.0.1:7008"})).To(Equal("127.0.0.1:7008"))
+		Expect(subject.Fastest(nil)).To(Equal(""))
+	})
+
+	It("should prefer unmeasured addresses", func() {
+		subject.Observe("127.0.0.1:7000", time.Millisecond)
+		Expect(subject.Fastest([]string{"127.0.0.1:7000", "127.0.0.1:7004"})).To(Equal("127.0.0.1:7004"))
+	})
+
+})
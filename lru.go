@@ -58,6 +58,16 @@ func (c *connLRU) Clear() {
 	}
 }
 
+// Remove closes and removes the addr's conn
+func (c *connLRU) Remove(addr string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if ele, hit := c.cache[addr]; hit {
+		c.remove(ele)
+	}
+}
+
 // Len returns the number of items in the cache.
 func (c *connLRU) len() int {
 	return c.ll.Len()
@@ -96,11 +106,13 @@ func (c *connLRU) get(addr string) (conn *redis.Client, ok bool) {
 // Temoves the oldest item in the cache.
 // If the cache is empty, the empty string and nil are returned.
 func (c *connLRU) removeOldest() {
-	ele := c.ll.Back()
-	if ele == nil {
-		return
+	if ele := c.ll.Back(); ele != nil {
+		c.remove(ele)
 	}
+}
 
+// Removes an item from the cache, closes the connection
+func (c *connLRU) remove(ele *list.Element) {
 	c.ll.Remove(ele)
 	ent := ele.Value.(*cachedConn)
 	delete(c.cache, ent.addr)
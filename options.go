@@ -1,18 +1,53 @@
 package cluster
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"time"
 
 	"gopkg.in/redis.v2"
 )
 
+// ReadMode determines how read-only commands are routed
+type ReadMode int
+
+const (
+	// ReadMaster sends all commands to the slot master (default)
+	ReadMaster ReadMode = iota
+	// ReadRandomReplica sends read-only commands to a random replica
+	ReadRandomReplica
+	// ReadRoundRobinReplica cycles read-only commands through the replicas
+	ReadRoundRobinReplica
+)
+
 type Options struct {
 	// A seed-list of host:port addresses of known cluster nodes
 	Addrs []string
 
-	// An optional password
+	// Static translation of node addresses, as announced by the
+	// cluster, to the addresses the client can reach, e.g. when
+	// nodes run behind NAT or in containers. Applied to CLUSTER
+	// SLOTS replies and to MOVED/ASK redirection targets.
+	AddrMap map[string]string
+
+	// An optional function to translate node addresses, applied
+	// to addresses that are not listed in AddrMap
+	AddrMapper func(string) string
+
+	// An optional ACL username and password
+	Username string
 	Password string
 
+	// An optional provider of credentials, called with the node
+	// address on every dial. Takes precedence over Username and
+	// Password, allows credentials to be rotated.
+	Credentials func(addr string) (username, password string, err error)
+
+	// TLS config for all node connections. Unless set, the
+	// server name is taken from the host of each node address.
+	TLSConfig *tls.Config
+
 	// The maximum number of open connections.
 	// Default: 10
 	//
@@ -24,8 +59,53 @@ type Options struct {
 
 	// The maximum number of TCP connections per
 	// Redis connection. Default: 10
+	//
+	// Commands of clients with cancellable contexts (see
+	// Client.WithContext) use a separate pool, which is
+	// limited to PoolSize TCP connections per node as well.
 	PoolSize int
 
+	// Routing of read-only commands. When replica reads are enabled,
+	// connections issue READONLY on dial. Slots without replicas, as
+	// well as replicas that cannot be reached, fall back to the master.
+	// Default: ReadMaster
+	ReadMode ReadMode
+
+	// Route read-only commands to the node with the lowest
+	// average round-trip time, master or replica. Takes
+	// precedence over ReadMode.
+	RouteByLatency bool
+
+	// The maximum number of retries after transient errors, i.e.
+	// TRYAGAIN and CLUSTERDOWN replies and connections errors
+	// on all known nodes. Default: 0 (no retries)
+	MaxRetries int
+
+	// Backoff between retries, doubled with every attempt up to
+	// MaxRetryBackoff, with random jitter.
+	// Default: 8ms, 512ms
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+
+	// The maximum total time spent on retries.
+	// Default: 0 (no limit)
+	RetryTimeout time.Duration
+
+	// Interval of the background refresh of the slot cache. The
+	// cache is only replaced when the cluster topology has changed.
+	// Default: 0 (disabled)
+	RefreshInterval time.Duration
+
+	// The minimum interval between slot cache reloads that are
+	// triggered by MOVED redirections or connection errors.
+	// Default: 100ms
+	MinReloadInterval time.Duration
+
+	// An optional callback, invoked when a reload installs a slot
+	// cache that differs from the previous one. It is not invoked
+	// on the initial load. Called synchronously, must not block.
+	OnTopologyChange func(*TopologyEvent)
+
 	DialTimeout  time.Duration
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
@@ -39,8 +119,73 @@ func (o *Options) maxConns() int {
 	return o.MaxConns
 }
 
+func (o *Options) minRetryBackoff() time.Duration {
+	if o.MinRetryBackoff < 1 {
+		return 8 * time.Millisecond
+	}
+	return o.MinRetryBackoff
+}
+
+func (o *Options) maxRetryBackoff() time.Duration {
+	if o.MaxRetryBackoff < 1 {
+		return 512 * time.Millisecond
+	}
+	return o.MaxRetryBackoff
+}
+
+// Translates an announced node address
+func (o *Options) mapAddr(addr string) string {
+	if mapped, ok := o.AddrMap[addr]; ok {
+		return mapped
+	}
+	if o.AddrMapper != nil {
+		return o.AddrMapper(addr)
+	}
+	return addr
+}
+
+func (o *Options) minReloadInterval() time.Duration {
+	if o.MinReloadInterval < 1 {
+		return 100 * time.Millisecond
+	}
+	return o.MinReloadInterval
+}
+
+func (o *Options) newRetry(ctx context.Context) *retry {
+	r := &retry{opts: o, ctx: ctx}
+	if o.RetryTimeout > 0 {
+		r.deadline = time.Now().Add(o.RetryTimeout)
+	}
+	if deadline, ok := ctx.Deadline(); ok && (r.deadline.IsZero() || deadline.Before(r.deadline)) {
+		r.deadline = deadline
+	}
+	return r
+}
+
+func (o *Options) readReplicas() bool {
+	return o.ReadMode != ReadMaster || o.RouteByLatency
+}
+
+func (o *Options) customDial() bool {
+	return o.readReplicas() || o.TLSConfig != nil || o.Username != "" || o.Credentials != nil
+}
+
+func (o *Options) credentials(addr string) (string, string, error) {
+	if o.Credentials != nil {
+		return o.Credentials(addr)
+	}
+	return o.Username, o.Password, nil
+}
+
+func (o *Options) dialTimeout() time.Duration {
+	if o.DialTimeout == 0 {
+		return 5 * time.Second
+	}
+	return o.DialTimeout
+}
+
 func (o *Options) options(addr string) *redis.Options {
-	return &redis.Options{
+	opts := &redis.Options{
 		Addr: addr,
 
 		Password: o.Password,
@@ -51,4 +196,13 @@ func (o *Options) options(addr string) *redis.Options {
 		WriteTimeout: o.WriteTimeout,
 		IdleTimeout:  o.IdleTimeout,
 	}
+
+	// Custom dialer, for TLS and ACL credentials, sends AUTH before READONLY
+	if o.customDial() {
+		opts.Password = ""
+		opts.Dialer = func() (net.Conn, error) {
+			return o.dial(addr)
+		}
+	}
+	return opts
 }
@@ -1,6 +1,9 @@
 package cluster
 
 import (
+	"crypto/tls"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"gopkg.in/redis.v2"
@@ -15,6 +18,72 @@ var _ = Describe("Options", func() {
 		}))
 	})
 
+	It("should use a custom dialer for replica reads", func() {
+		opts := &Options{Password: "secret", ReadMode: ReadRandomReplica}
+		ropts := opts.options("127.0.0.1:7001")
+		Expect(ropts.Addr).To(Equal("127.0.0.1:7001"))
+		Expect(ropts.Password).To(Equal(""))
+		Expect(ropts.Dialer).NotTo(BeNil())
+
+		opts = &Options{RouteByLatency: true}
+		Expect(opts.options("127.0.0.1:7001").Dialer).NotTo(BeNil())
+	})
+
+	It("should use a custom dialer for TLS", func() {
+		opts := &Options{Password: "secret", TLSConfig: &tls.Config{}}
+		ropts := opts.options("127.0.0.1:7001")
+		Expect(ropts.Password).To(Equal(""))
+		Expect(ropts.Dialer).NotTo(BeNil())
+	})
+
+	It("should use a custom dialer for ACL credentials", func() {
+		opts := &Options{Username: "app", Password: "secret"}
+		Expect(opts.options("127.0.0.1:7001").Dialer).NotTo(BeNil())
+
+		opts = &Options{Credentials: func(string) (string, string, error) { return "", "", nil }}
+		Expect(opts.options("127.0.0.1:7001").Dialer).NotTo(BeNil())
+	})
+
+	It("should derive TLS server names", func() {
+		opts := &Options{TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12}}
+		cfg := opts.tlsConfig("redis-1.example.com:7001")
+		Expect(cfg.ServerName).To(Equal("redis-1.example.com"))
+		Expect(cfg.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(opts.TLSConfig.ServerName).To(Equal(""))
+
+		opts.TLSConfig.ServerName = "redis.example.com"
+		Expect(opts.tlsConfig("redis-1.example.com:7001").ServerName).To(Equal("redis.example.com"))
+	})
+
+	It("should have retry backoff defaults", func() {
+		opts := &Options{}
+		Expect(opts.minRetryBackoff()).To(Equal(8 * time.Millisecond))
+		Expect(opts.maxRetryBackoff()).To(Equal(512 * time.Millisecond))
+		opts.MinRetryBackoff, opts.MaxRetryBackoff = time.Millisecond, time.Second
+		Expect(opts.minRetryBackoff()).To(Equal(time.Millisecond))
+		Expect(opts.maxRetryBackoff()).To(Equal(time.Second))
+	})
+
+	It("should map addresses", func() {
+		opts := &Options{}
+		Expect(opts.mapAddr("172.17.0.2:7000")).To(Equal("172.17.0.2:7000"))
+
+		opts.AddrMap = map[string]string{"172.17.0.2:7000": "127.0.0.1:7000"}
+		Expect(opts.mapAddr("172.17.0.2:7000")).To(Equal("127.0.0.1:7000"))
+		Expect(opts.mapAddr("172.17.0.3:7001")).To(Equal("172.17.0.3:7001"))
+
+		opts.AddrMapper = func(addr string) string { return "localhost" + addr[len("172.17.0.3"):] }
+		Expect(opts.mapAddr("172.17.0.2:7000")).To(Equal("127.0.0.1:7000"))
+		Expect(opts.mapAddr("172.17.0.3:7001")).To(Equal("localhost:7001"))
+	})
+
+	It("should have a reload interval default", func() {
+		opts := &Options{}
+		Expect(opts.minReloadInterval()).To(Equal(100 * time.Millisecond))
+		opts.MinReloadInterval = time.Second
+		Expect(opts.minReloadInterval()).To(Equal(time.Second))
+	})
+
 	It("should have a max-conn default", func() {
 		opts := &Options{}
 		Expect(opts.maxConns()).To(Equal(10))
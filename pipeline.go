@@ -0,0 +1,233 @@
+package cluster
+
+import (
+	"errors"
+	"sync"
+
+	"gopkg.in/redis.v2"
+)
+
+var errClosedPipeline = errors.New("redis cluster: pipeline is closed")
+
+// Pipeline queues commands and sends them to the cluster in batches,
+// one batch per node. Not thread-safe.
+type Pipeline struct {
+	commandable
+
+	client *Client
+	cmds   []*pipelineCmd
+	closed bool
+}
+
+type pipelineCmd struct {
+	hashSlot int
+	cmd      redis.Cmder
+
+	readOnly bool
+
+	addr   string
+	failed string
+	ask    bool
+}
+
+// Pipeline creates a new pipeline
+func (c *Client) Pipeline() *Pipeline {
+	pipe := &Pipeline{client: c}
+	pipe.commandable.process = pipe.queue
+	return pipe
+}
+
+// Pipelined queues the commands issued by fn and executes them
+func (c *Client) Pipelined(fn func(*Pipeline) error) ([]redis.Cmder, error) {
+	pipe := c.Pipeline()
+	defer pipe.Close()
+
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+	return pipe.Exec()
+}
+
+// Queues a single command for a hashSlot
+func (p *Pipeline) queue(hashSlot int, cmd redis.Cmder, readOnly bool) {
+	p.cmds = append(p.cmds, &pipelineCmd{hashSlot: hashSlot, cmd: cmd, readOnly: readOnly})
+}
+
+// Close closes the pipeline
+func (p *Pipeline) Close() error {
+	p.closed = true
+	return nil
+}
+
+// Discard drops all queued commands
+func (p *Pipeline) Discard() error {
+	if p.closed {
+		return errClosedPipeline
+	}
+	p.cmds = p.cmds[:0]
+	return nil
+}
+
+// Exec sends all queued commands to the nodes serving their slots. Commands
+// for the same node are pipelined together, nodes are processed in parallel.
+// Only commands that return a MOVED or ASK redirection or a transient error
+// (see Options.MaxRetries) are retried. Exec always returns the list of
+// commands and the error of the first failed command, if any.
+func (p *Pipeline) Exec() ([]redis.Cmder, error) {
+	if p.closed {
+		return nil, errClosedPipeline
+	}
+
+	pending := p.cmds
+	p.cmds = nil
+
+	cmds := make([]redis.Cmder, len(pending))
+	for i, pc := range pending {
+		cmds[i] = pc.cmd
+	}
+	if len(pending) == 0 {
+		return cmds, nil
+	}
+
+	p.client.processPipeline(pending)
+
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			return cmds, err
+		}
+	}
+	return cmds, nil
+}
+
+// Processes pipelined commands, grouped by node. Retries commands with
+// transient errors according to the retry policy
+func (c *Client) processPipeline(pending []*pipelineCmd) {
+	all := pending
+	retry := c.opts.newRetry(c.ctx)
+	for len(pending) > 0 {
+		c.tryPipeline(pending)
+
+		// Collect commands with transient errors
+		var transient []*pipelineCmd
+		reload := false
+		for _, pc := range pending {
+			if err := pc.cmd.Err(); isRetryable(err) {
+				transient = append(transient, pc)
+				reload = reload || !isTryAgain(err)
+			}
+		}
+		if len(transient) == 0 || !retry.Wait() {
+			break
+		}
+		if reload {
+			c.forceReloadOnNextCommand()
+		}
+		pending = transient
+	}
+
+	for _, pc := range all {
+		wrapCmdErr(pc.cmd, pc.failed)
+	}
+}
+
+// Applies pipelined commands, follows redirects and falls back to
+// other nodes on connection errors
+func (c *Client) tryPipeline(pending []*pipelineCmd) {
+	if err := c.ctx.Err(); err != nil {
+		for _, pc := range pending {
+			setCmdErr(pc.cmd, err)
+		}
+		return
+	}
+	if c.reloadDue() {
+		c.reload(c.ctx)
+	}
+
+	topo := c.topo()
+	for _, pc := range pending {
+		pc.ask = false
+		if pc.readOnly {
+			pc.addr = c.slotReadAddr(topo, pc.hashSlot)
+		} else {
+			pc.addr = topo.slotAddr(pc.hashSlot)
+		}
+	}
+
+	tried := make(map[string]struct{}, len(topo.addrs))
+	for attempt := 0; attempt < MaxRedirects && len(pending) > 0; attempt++ {
+		if err := c.ctx.Err(); err != nil {
+			for _, pc := range pending {
+				setCmdErr(pc.cmd, err)
+			}
+			return
+		}
+
+		// Group commands by node
+		groups := make(map[string][]*pipelineCmd)
+		for _, pc := range pending {
+			groups[pc.addr] = append(groups[pc.addr], pc)
+		}
+
+		// Process each group in parallel
+		var wait sync.WaitGroup
+		for addr, group := range groups {
+			tried[addr] = struct{}{}
+
+			wait.Add(1)
+			go func(addr string, group []*pipelineCmd) {
+				defer wait.Done()
+				c.execPipeline(addr, group)
+			}(addr, group)
+		}
+		wait.Wait()
+
+		// Collect commands that need to be retried
+		var retry []*pipelineCmd
+		for _, pc := range pending {
+			err := pc.cmd.Err()
+			if err == nil || err == redis.Nil {
+				continue
+			}
+			pc.failed = pc.addr
+
+			// On connection errors, pick the next (not previosuly) tried connection
+			if isConnError(err) {
+				if pc.addr = topo.retryAddr(pc.hashSlot, tried); pc.addr != "" {
+					retry = append(retry, pc)
+				}
+				continue
+			}
+
+			// Handle MOVE and ASK redirections, skip on any other error
+			switch e := parseError(err).(type) {
+			case *MovedError:
+				c.forceReloadOnNextCommand()
+				pc.addr = c.opts.mapAddr(e.Addr)
+			case *AskError:
+				pc.ask = true
+				pc.addr = c.opts.mapAddr(e.Addr)
+			default:
+				continue
+			}
+			retry = append(retry, pc)
+		}
+		pending = retry
+	}
+}
+
+// Sends a group of commands to a single node
+func (c *Client) execPipeline(addr string, group []*pipelineCmd) {
+	conn := c.conns.Fetch(addr, c.connectTo)
+	pipe := conn.Pipeline()
+	defer pipe.Close()
+
+	for _, pc := range group {
+		pc.cmd.Reset()
+		if pc.ask {
+			pipe.Process(redis.NewCmd("ASKING"))
+			pc.ask = false
+		}
+		pipe.Process(pc.cmd)
+	}
+	_, _ = pipe.Exec()
+}
@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("Pipeline", func() {
+	var client *Client
+	var subject *Pipeline
+
+	BeforeEach(func() {
+		client = newClient(&Options{Addrs: []string{"127.0.0.1:6379"}})
+		subject = client.Pipeline()
+	})
+
+	AfterEach(func() {
+		subject.Close()
+		client.Close()
+	})
+
+	It("should queue commands", func() {
+		get := subject.Get("foo")
+		set := subject.Set("bar", "baz")
+		Expect(subject.cmds).To(HaveLen(2))
+		Expect(subject.cmds[0].hashSlot).To(Equal(HashSlot("foo")))
+		Expect(subject.cmds[0].cmd).To(Equal(redis.Cmder(get)))
+		Expect(subject.cmds[1].hashSlot).To(Equal(HashSlot("bar")))
+		Expect(subject.cmds[1].cmd).To(Equal(redis.Cmder(set)))
+	})
+
+	It("should discard commands", func() {
+		subject.Get("foo")
+		Expect(subject.Discard()).To(Succeed())
+		Expect(subject.cmds).To(BeEmpty())
+	})
+
+	It("should execute empty pipelines", func() {
+		cmds, err := subject.Exec()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds).To(BeEmpty())
+	})
+
+	It("should fail when closed", func() {
+		Expect(subject.Close()).To(Succeed())
+		Expect(subject.Discard()).To(Equal(errClosedPipeline))
+		_, err := subject.Exec()
+		Expect(err).To(Equal(errClosedPipeline))
+	})
+
+})
+
+var _ = Describe("Pipeline execution", func() {
+	fake := newFakeCluster()
+	var subject *Pipeline
+
+	var receive = func(node *fakeNode, cmds ...[]string) {
+		for _, cmd := range cmds {
+			Eventually(node.cmds).Should(Receive(Equal(cmd)))
+		}
+	}
+
+	BeforeEach(func() {
+		// Keys {bar}* hash to slot 5061, {foo}* to slot 12182
+		subject = fake.client.Pipeline()
+		subject.Get("{bar}1")
+		subject.Get("{foo}1")
+		subject.Get("{bar}2")
+	})
+
+	AfterEach(func() {
+		subject.Close()
+	})
+
+	It("should group commands by node and process fake.nodes in parallel", func() {
+		go func() {
+			defer GinkgoRecover()
+
+			// Both fake.nodes receive their batches before either replies
+			receive(fake.nodes[0], []string{"GET", "{bar}1"}, []string{"GET", "{bar}2"})
+			receive(fake.nodes[1], []string{"GET", "{foo}1"})
+			fake.nodes[0].Reply("$1\r\na\r\n$1\r\nc\r\n")
+			fake.nodes[1].Reply("$1\r\nb\r\n")
+		}()
+
+		cmds, err := subject.Exec()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds).To(HaveLen(3))
+		Expect(cmds[0].(*redis.StringCmd).Val()).To(Equal("a"))
+		Expect(cmds[1].(*redis.StringCmd).Val()).To(Equal("b"))
+		Expect(cmds[2].(*redis.StringCmd).Val()).To(Equal("c"))
+	})
+
+	It("should only re-send moved commands", func() {
+		go func() {
+			defer GinkgoRecover()
+
+			receive(fake.nodes[0], []string{"GET", "{bar}1"}, []string{"GET", "{bar}2"})
+			receive(fake.nodes[1], []string{"GET", "{foo}1"})
+			fake.nodes[0].Reply("$1\r\na\r\n-MOVED 5061 " + fake.nodes[1].Addr() + "\r\n")
+			fake.nodes[1].Reply("$1\r\nb\r\n")
+
+			receive(fake.nodes[1], []string{"GET", "{bar}2"})
+			fake.nodes[1].Reply("$1\r\nc\r\n")
+		}()
+
+		cmds, err := subject.Exec()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds[0].(*redis.StringCmd).Val()).To(Equal("a"))
+		Expect(cmds[1].(*redis.StringCmd).Val()).To(Equal("b"))
+		Expect(cmds[2].(*redis.StringCmd).Val()).To(Equal("c"))
+		Consistently(fake.nodes[0].cmds, "50ms").ShouldNot(Receive())
+		Consistently(fake.nodes[1].cmds, "50ms").ShouldNot(Receive())
+	})
+
+	It("should only re-send asked commands", func() {
+		go func() {
+			defer GinkgoRecover()
+
+			receive(fake.nodes[0], []string{"GET", "{bar}1"}, []string{"GET", "{bar}2"})
+			receive(fake.nodes[1], []string{"GET", "{foo}1"})
+			fake.nodes[0].Reply("-ASK 5061 " + fake.nodes[1].Addr() + "\r\n$1\r\nc\r\n")
+			fake.nodes[1].Reply("$1\r\nb\r\n")
+
+			receive(fake.nodes[1], []string{"ASKING"}, []string{"GET", "{bar}1"})
+			fake.nodes[1].Reply("+OK\r\n$1\r\na\r\n")
+		}()
+
+		cmds, err := subject.Exec()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds[0].(*redis.StringCmd).Val()).To(Equal("a"))
+		Expect(cmds[1].(*redis.StringCmd).Val()).To(Equal("b"))
+		Expect(cmds[2].(*redis.StringCmd).Val()).To(Equal("c"))
+		Consistently(fake.nodes[0].cmds, "50ms").ShouldNot(Receive())
+		Consistently(fake.nodes[1].cmds, "50ms").ShouldNot(Receive())
+	})
+
+})
@@ -0,0 +1,230 @@
+package cluster
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gopkg.in/redis.v2"
+)
+
+var errClosedPubSub = errors.New("redis cluster: pubsub is closed")
+
+// PubSub subscribes to channels and patterns over a dedicated
+// connection to a single node. Messages are propagated to all nodes of
+// the cluster, when the node fails, PubSub reconnects to another one and
+// restores all subscriptions. Thread-safe, subscriptions may be changed
+// and the subscriber closed while Receive is blocked.
+type PubSub struct {
+	client *Client
+
+	lock   sync.Mutex
+	addr   string
+	conn   *redis.Client
+	pubsub *redis.PubSub
+	tried  map[string]struct{}
+
+	channels map[string]struct{}
+	patterns map[string]struct{}
+	closed   bool
+}
+
+// PubSub creates a new subscriber
+func (c *Client) PubSub() *PubSub {
+	return &PubSub{
+		client:   c,
+		tried:    make(map[string]struct{}),
+		channels: make(map[string]struct{}),
+		patterns: make(map[string]struct{}),
+	}
+}
+
+// Subscribe subscribes to channels
+func (p *PubSub) Subscribe(channels ...string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, ch := range channels {
+		p.channels[ch] = struct{}{}
+	}
+	return p.apply(func(ps *redis.PubSub) error { return ps.Subscribe(channels...) })
+}
+
+// PSubscribe subscribes to patterns
+func (p *PubSub) PSubscribe(patterns ...string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, pt := range patterns {
+		p.patterns[pt] = struct{}{}
+	}
+	return p.apply(func(ps *redis.PubSub) error { return ps.PSubscribe(patterns...) })
+}
+
+// Unsubscribe unsubscribes from channels
+func (p *PubSub) Unsubscribe(channels ...string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, ch := range channels {
+		delete(p.channels, ch)
+	}
+	return p.apply(func(ps *redis.PubSub) error { return ps.Unsubscribe(channels...) })
+}
+
+// PUnsubscribe unsubscribes from patterns
+func (p *PubSub) PUnsubscribe(patterns ...string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, pt := range patterns {
+		delete(p.patterns, pt)
+	}
+	return p.apply(func(ps *redis.PubSub) error { return ps.PUnsubscribe(patterns...) })
+}
+
+// Receive waits for the next message, see ReceiveTimeout
+func (p *PubSub) Receive() (interface{}, error) {
+	return p.ReceiveTimeout(0)
+}
+
+// ReceiveTimeout waits for the next *redis.Message, *redis.PMessage or
+// *redis.Subscription. Reconnects on connection errors, a zero timeout
+// waits forever.
+func (p *PubSub) ReceiveTimeout(timeout time.Duration) (interface{}, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var err error
+	for attempt := 0; attempt < MaxRedirects; attempt++ {
+		if p.closed {
+			return nil, errClosedPubSub
+		}
+		if _, err = p.connect(); err != nil {
+			return nil, err
+		}
+
+		// Wait without the lock, the connection may be replaced
+		// or closed in the meantime
+		pubsub := p.pubsub
+		p.lock.Unlock()
+		msg, e := pubsub.ReceiveTimeout(timeout)
+		p.lock.Lock()
+
+		if err = e; err == nil {
+			p.tried = make(map[string]struct{})
+			return msg, nil
+		} else if p.closed {
+			return nil, errClosedPubSub
+		} else if p.pubsub != pubsub {
+			continue
+		} else if isTimeout(err) || !isConnError(err) {
+			return nil, err
+		}
+		p.reset()
+	}
+	return nil, err
+}
+
+// Addr returns the address of the node currently subscribed to
+func (p *PubSub) Addr() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.addr
+}
+
+// Close closes the connection, a blocked Receive returns
+func (p *PubSub) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.closed = true
+	p.reset()
+	return nil
+}
+
+// Applies a subscription change, reconnects on connection errors.
+// New connections restore all subscriptions, fn is skipped. Must be
+// called with the lock held.
+func (p *PubSub) apply(fn func(*redis.PubSub) error) error {
+	if p.closed {
+		return errClosedPubSub
+	}
+
+	var err error
+	for attempt := 0; attempt < MaxRedirects; attempt++ {
+		var fresh bool
+		if fresh, err = p.connect(); err != nil || fresh {
+			return err
+		}
+		if err = fn(p.pubsub); !isConnError(err) {
+			return err
+		}
+		p.reset()
+	}
+	return err
+}
+
+// Connects to the next untried node and restores all subscriptions,
+// returns true if a new connection was established. Must be called
+// with the lock held.
+func (p *PubSub) connect() (bool, error) {
+	if p.pubsub != nil {
+		return false, nil
+	}
+
+	err := errNoAddresses
+	for _, addr := range p.client.topo().addrs {
+		if _, ok := p.tried[addr]; ok {
+			continue
+		}
+		p.tried[addr] = struct{}{}
+
+		conn := p.client.connectTo(addr)
+		pubsub := conn.PubSub()
+		if err = p.restore(pubsub); err == nil {
+			p.addr, p.conn, p.pubsub = addr, conn, pubsub
+			return true, nil
+		}
+		pubsub.Close()
+		conn.Close()
+	}
+
+	// All nodes failed, start over next time
+	p.tried = make(map[string]struct{})
+	return false, err
+}
+
+// Restores all subscriptions on a new connection
+func (p *PubSub) restore(pubsub *redis.PubSub) error {
+	if len(p.channels) != 0 {
+		if err := pubsub.Subscribe(setKeys(p.channels)...); err != nil {
+			return err
+		}
+	}
+	if len(p.patterns) != 0 {
+		if err := pubsub.PSubscribe(setKeys(p.patterns)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Closes the current connection. Must be called with the lock held.
+func (p *PubSub) reset() {
+	if p.pubsub != nil {
+		p.pubsub.Close()
+		p.conn.Close()
+	}
+	p.addr, p.conn, p.pubsub = "", nil, nil
+}
+
+// Returns the members of a set
+func setKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("PubSub", func() {
+	var client *Client
+	var subject *PubSub
+	var nodes []*fakeNode
+
+	BeforeEach(func() {
+		nodes = []*fakeNode{newFakeNode(), newFakeNode()}
+		client = newClient(&Options{Addrs: []string{nodes[0].Addr(), nodes[1].Addr()}})
+		subject = client.PubSub()
+	})
+
+	AfterEach(func() {
+		subject.Close()
+		client.Close()
+		for _, node := range nodes {
+			node.Close()
+		}
+	})
+
+	It("should subscribe on a single node", func() {
+		Expect(subject.Subscribe("news")).To(Succeed())
+		Expect(subject.Addr()).To(Equal(nodes[0].Addr()))
+		Eventually(nodes[0].cmds).Should(Receive(Equal([]string{"SUBSCRIBE", "news"})))
+
+		Expect(subject.PSubscribe("ne*")).To(Succeed())
+		Eventually(nodes[0].cmds).Should(Receive(Equal([]string{"PSUBSCRIBE", "ne*"})))
+
+		nodes[0].Reply("*3\r\n$7\r\nmessage\r\n$4\r\nnews\r\n$5\r\nhello\r\n")
+		Expect(subject.Receive()).To(Equal(&redis.Message{Channel: "news", Payload: "hello"}))
+	})
+
+	It("should reconnect and restore subscriptions", func() {
+		Expect(subject.Subscribe("news")).To(Succeed())
+		Eventually(nodes[0].cmds).Should(Receive())
+
+		Expect(subject.PSubscribe("ne*")).To(Succeed())
+		Eventually(nodes[0].cmds).Should(Receive())
+
+		nodes[0].Close()
+		go nodes[1].Reply("*3\r\n$7\r\nmessage\r\n$4\r\nnews\r\n$5\r\nagain\r\n")
+
+		Expect(subject.Receive()).To(Equal(&redis.Message{Channel: "news", Payload: "again"}))
+		Expect(subject.Addr()).To(Equal(nodes[1].Addr()))
+		Eventually(nodes[1].cmds).Should(Receive(Equal([]string{"SUBSCRIBE", "news"})))
+		Eventually(nodes[1].cmds).Should(Receive(Equal([]string{"PSUBSCRIBE", "ne*"})))
+	})
+
+	It("should change subscriptions and close while receiving", func() {
+		Expect(subject.Subscribe("news")).To(Succeed())
+		Eventually(nodes[0].cmds).Should(Receive())
+
+		receive := func() <-chan interface{} {
+			res := make(chan interface{}, 1)
+			go func() {
+				msg, err := subject.Receive()
+				if err != nil {
+					res <- err
+					return
+				}
+				res <- msg
+			}()
+			return res
+		}
+
+		res := receive()
+		Consistently(res, "50ms").ShouldNot(Receive())
+		Expect(subject.Subscribe("sports")).To(Succeed())
+		Eventually(nodes[0].cmds).Should(Receive(Equal([]string{"SUBSCRIBE", "sports"})))
+		nodes[0].Reply("*3\r\n$7\r\nmessage\r\n$6\r\nsports\r\n$4\r\ngoal\r\n")
+		Eventually(res).Should(Receive(Equal(&redis.Message{Channel: "sports", Payload: "goal"})))
+
+		res = receive()
+		Consistently(res, "50ms").ShouldNot(Receive())
+		Expect(subject.Close()).To(Succeed())
+		Eventually(res).Should(Receive(Equal(errClosedPubSub)))
+	})
+
+	It("should track subscriptions", func() {
+		Expect(subject.Subscribe("a", "b")).To(Succeed())
+		Expect(subject.Unsubscribe("a")).To(Succeed())
+		Expect(subject.channels).To(HaveKey("b"))
+		Expect(subject.channels).NotTo(HaveKey("a"))
+	})
+
+	It("should fail when closed", func() {
+		Expect(subject.Close()).To(Succeed())
+		Expect(subject.Subscribe("news")).To(Equal(errClosedPubSub))
+		_, err := subject.Receive()
+		Expect(err).To(Equal(errClosedPubSub))
+	})
+
+})
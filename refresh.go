@@ -0,0 +1,44 @@
+package cluster
+
+import "time"
+
+// Periodically refreshes the slot cache until the client is closed
+func (c *Client) refreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closing:
+			return
+		case <-ticker.C:
+			_ = c.reload(c.ctx)
+		}
+	}
+}
+
+// Compares two slot tables
+func equalSlots(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equalAddrs(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compares two lists of addresses
+func equalAddrs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
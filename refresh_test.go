@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("refresh", func() {
+	var infos = []slotInfo{
+		{min: 0, max: 8191, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7002"}},
+		{min: 8192, max: 16383, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7003"}},
+	}
+
+	It("should build slot tables", func() {
+		slots := newSlotTable(infos)
+		Expect(slots).To(HaveLen(HashSlots))
+		Expect(slots[0]).To(Equal([]string{"127.0.0.1:7000", "127.0.0.1:7002"}))
+		Expect(slots[8192]).To(Equal([]string{"127.0.0.1:7001", "127.0.0.1:7003"}))
+		Expect(newSlotTable(nil)[0]).To(BeEmpty())
+	})
+
+	It("should compare slot tables", func() {
+		Expect(equalSlots(newSlotTable(infos), newSlotTable(infos))).To(BeTrue())
+		Expect(equalSlots(newSlotTable(infos), newSlotTable(nil))).To(BeFalse())
+		Expect(equalSlots(newSlotTable(infos), nil)).To(BeFalse())
+
+		failover := newSlotTable(infos)
+		failover[100] = []string{"127.0.0.1:7002", "127.0.0.1:7000"}
+		Expect(equalSlots(newSlotTable(infos), failover)).To(BeFalse())
+	})
+
+	It("should keep the slot cache when no node is reachable", func() {
+		subject := newClient(&Options{Addrs: []string{"127.0.0.1:1"}})
+		defer subject.Close()
+
+		subject.cacheSlots(infos)
+		Expect(subject.reload(context.Background())).To(HaveOccurred())
+		Expect(subject.topo().slots[0]).To(Equal([]string{"127.0.0.1:7000", "127.0.0.1:7002"}))
+	})
+
+	It("should stop when the client is closed", func() {
+		subject := newClient(&Options{})
+		done := make(chan struct{})
+		go func() {
+			subject.refreshLoop(time.Hour)
+			close(done)
+		}()
+
+		Consistently(done).ShouldNot(BeClosed())
+		Expect(subject.Close()).To(Succeed())
+		Eventually(done).Should(BeClosed())
+		Expect(subject.Close()).To(Succeed())
+	})
+
+})
@@ -0,0 +1,151 @@
+package cluster
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/redis.v2"
+)
+
+// The setters of redis.v2 commands are unexported, commands can only be
+// populated by a redis.Client. To return merged results from multiple
+// nodes, commands are processed on clients that either fail to dial or
+// replay a locally encoded reply.
+
+// Fails a command with err
+func setCmdErr(cmd redis.Cmder, err error) {
+	failer.Lock()
+	defer failer.Unlock()
+
+	failer.err, failer.dialed = err, false
+	failer.conn.Process(cmd)
+
+	// Dials are rate-limited, replace the client once exhausted
+	if !failer.dialed {
+		failer.conn.Close()
+		failer.conn = failer.newConn()
+		failer.conn.Process(cmd)
+	}
+	failer.err = nil
+}
+
+// failer is a shared client, which fails to dial with one error
+// at a time
+var failer = newFailClient()
+
+type failClient struct {
+	err    error
+	dialed bool
+	conn   *redis.Client
+
+	sync.Mutex
+}
+
+func newFailClient() *failClient {
+	f := new(failClient)
+	f.conn = f.newConn()
+	return f
+}
+
+// Creates a client, redis.v2 allows 2 x PoolSize dials before rate-limiting
+func (f *failClient) newConn() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		PoolSize: 512,
+		Dialer: func() (net.Conn, error) {
+			f.dialed = true
+			return nil, f.err
+		},
+	})
+}
+
+// Populates a command with a reply value. Supported values are nil,
+// string, int64, bool, []string and []interface{} of these.
+func setCmdVal(cmd redis.Cmder, val interface{}) {
+	replayer.Lock()
+	defer replayer.Unlock()
+
+	replayer.reply.Reset(appendReply(nil, val))
+	replayer.conn.Process(cmd)
+}
+
+// replayer is a shared client, which replays one reply at a time
+var replayer = newReplayClient()
+
+type replayClient struct {
+	reply *bytes.Reader
+	conn  *redis.Client
+
+	sync.Mutex
+}
+
+func newReplayClient() *replayClient {
+	r := &replayClient{reply: bytes.NewReader(nil)}
+	r.conn = redis.NewClient(&redis.Options{
+		PoolSize: 1,
+		Dialer:   func() (net.Conn, error) { return &replyConn{Reader: r.reply}, nil },
+	})
+	return r
+}
+
+// Encodes a value in the redis protocol
+func appendReply(buf []byte, val interface{}) []byte {
+	switch v := val.(type) {
+	case nil:
+		buf = append(buf, "$-1\r\n"...)
+	case string:
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(v)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, v...)
+		buf = append(buf, '\r', '\n')
+	case int64:
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, v, 10)
+		buf = append(buf, '\r', '\n')
+	case bool:
+		if v {
+			buf = append(buf, ":1\r\n"...)
+		} else {
+			buf = append(buf, ":0\r\n"...)
+		}
+	case []string:
+		buf = append(buf, '*')
+		buf = strconv.AppendInt(buf, int64(len(v)), 10)
+		buf = append(buf, '\r', '\n')
+		for _, s := range v {
+			buf = appendReply(buf, s)
+		}
+	case []interface{}:
+		buf = append(buf, '*')
+		buf = strconv.AppendInt(buf, int64(len(v)), 10)
+		buf = append(buf, '\r', '\n')
+		for _, e := range v {
+			buf = appendReply(buf, e)
+		}
+	default:
+		panic("redis cluster: cannot encode reply value")
+	}
+	return buf
+}
+
+// replyConn is an in-memory connection which
+// discards all writes and replays a fixed reply
+type replyConn struct {
+	*bytes.Reader
+}
+
+func (c *replyConn) Write(b []byte) (int, error)        { return len(b), nil }
+func (c *replyConn) Close() error                       { return nil }
+func (c *replyConn) LocalAddr() net.Addr                { return replyAddr{} }
+func (c *replyConn) RemoteAddr() net.Addr               { return replyAddr{} }
+func (c *replyConn) SetDeadline(_ time.Time) error      { return nil }
+func (c *replyConn) SetReadDeadline(_ time.Time) error  { return nil }
+func (c *replyConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+type replyAddr struct{}
+
+func (replyAddr) Network() string { return "memory" }
+func (replyAddr) String() string  { return "memory" }
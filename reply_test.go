@@ -0,0 +1,73 @@
+package cluster
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("reply", func() {
+
+	It("should encode values", func() {
+		Expect(string(appendReply(nil, nil))).To(Equal("$-1\r\n"))
+		Expect(string(appendReply(nil, "foo"))).To(Equal("$3\r\nfoo\r\n"))
+		Expect(string(appendReply(nil, int64(12)))).To(Equal(":12\r\n"))
+		Expect(string(appendReply(nil, true))).To(Equal(":1\r\n"))
+		Expect(string(appendReply(nil, []string{"a", "b"}))).To(Equal("*2\r\n$1\r\na\r\n$1\r\nb\r\n"))
+		Expect(string(appendReply(nil, []interface{}{"a", nil}))).To(Equal("*2\r\n$1\r\na\r\n$-1\r\n"))
+	})
+
+	It("should populate commands", func() {
+		slice := redis.NewSliceCmd("MGET", "a", "b")
+		setCmdVal(slice, []interface{}{"x", nil})
+		Expect(slice.Result()).To(Equal([]interface{}{"x", nil}))
+
+		smap := redis.NewStringStringMapCmd("MGET", "a", "b")
+		setCmdVal(smap, []string{"a", "x"})
+		Expect(smap.Result()).To(Equal(map[string]string{"a": "x"}))
+
+		num := redis.NewIntCmd("DEL", "a", "b")
+		setCmdVal(num, int64(2))
+		Expect(num.Result()).To(Equal(int64(2)))
+	})
+
+	It("should populate commands concurrently", func() {
+		var wg sync.WaitGroup
+		for i := int64(0); i < 20; i++ {
+			wg.Add(1)
+			go func(n int64) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				cmd := redis.NewIntCmd("DEL", "a")
+				setCmdVal(cmd, n)
+				Expect(cmd.Result()).To(Equal(n))
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	It("should fail commands", func() {
+		err := errors.New("failed")
+		cmd := redis.NewStatusCmd("PING")
+		setCmdErr(cmd, err)
+		Expect(cmd.Err()).To(Equal(err))
+	})
+
+	It("should fail commands on a shared client", func() {
+		before := runtime.NumGoroutine()
+		for i := 0; i < 5000; i++ {
+			err := errors.New("failed " + strconv.Itoa(i))
+			cmd := redis.NewStatusCmd("PING")
+			setCmdErr(cmd, err)
+			Expect(cmd.Err()).To(Equal(err))
+		}
+		Expect(runtime.NumGoroutine() - before).To(BeNumerically("<", 10))
+	})
+
+})
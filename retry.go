@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// retry tracks the attempts of a single request
+type retry struct {
+	opts     *Options
+	ctx      context.Context
+	attempt  int
+	deadline time.Time
+}
+
+// Wait sleeps before the next attempt, returns false if no more
+// attempts are allowed or the context is done
+func (r *retry) Wait() bool {
+	if r.attempt >= r.opts.MaxRetries {
+		return false
+	}
+
+	backoff := r.Backoff()
+	if !r.deadline.IsZero() && time.Now().Add(backoff).After(r.deadline) {
+		return false
+	}
+
+	r.attempt++
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.ctx.Done():
+		return false
+	}
+}
+
+// Backoff calculates the backoff for the next attempt: exponential,
+// capped, with the upper half randomised
+func (r *retry) Backoff() time.Duration {
+	backoff, max := r.opts.minRetryBackoff(), r.opts.maxRetryBackoff()
+	for i := 0; i < r.attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
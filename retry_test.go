@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("retry", func() {
+
+	It("should calculate exponential backoffs", func() {
+		subject := (&Options{MinRetryBackoff: 10 * time.Millisecond, MaxRetryBackoff: 35 * time.Millisecond}).newRetry(context.Background())
+		Expect(subject.Backoff()).To(BeNumerically("~", 7500*time.Microsecond, 2500*time.Microsecond))
+		subject.attempt = 1
+		Expect(subject.Backoff()).To(BeNumerically("~", 15*time.Millisecond, 5*time.Millisecond))
+		subject.attempt = 2
+		Expect(subject.Backoff()).To(BeNumerically("~", 26250*time.Microsecond, 8750*time.Microsecond))
+		subject.attempt = 20
+		Expect(subject.Backoff()).To(BeNumerically("~", 26250*time.Microsecond, 8750*time.Microsecond))
+	})
+
+	It("should limit attempts", func() {
+		subject := (&Options{MaxRetries: 2, MinRetryBackoff: time.Microsecond}).newRetry(context.Background())
+		Expect(subject.Wait()).To(BeTrue())
+		Expect(subject.Wait()).To(BeTrue())
+		Expect(subject.Wait()).To(BeFalse())
+
+		subject = (&Options{}).newRetry(context.Background())
+		Expect(subject.Wait()).To(BeFalse())
+	})
+
+	It("should respect deadlines", func() {
+		subject := (&Options{MaxRetries: 10, MinRetryBackoff: 50 * time.Millisecond, RetryTimeout: 10 * time.Millisecond}).newRetry(context.Background())
+		Expect(subject.Wait()).To(BeFalse())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		subject = (&Options{MaxRetries: 10, MinRetryBackoff: 50 * time.Millisecond}).newRetry(ctx)
+		Expect(subject.Wait()).To(BeFalse())
+	})
+
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		subject := (&Options{MaxRetries: 10, MinRetryBackoff: 50 * time.Millisecond}).newRetry(ctx)
+		Expect(subject.Wait()).To(BeFalse())
+	})
+
+})
@@ -0,0 +1,323 @@
+package cluster
+
+import (
+	"strconv"
+
+	"gopkg.in/redis.v2"
+)
+
+// ScanIterator iterates over the keys of the whole cluster by walking
+// SCAN cursors on every master, one after another. When a node fails or
+// the topology changes during the walk, the slot cache is reloaded and the
+// affected slots are scanned again on their new masters. A node walk that
+// failed resumes from its cursor when the node is visited again. Keys of
+// slots that moved away during a walk are remembered, so they are not
+// returned twice. Other keys may be returned more than once, like with
+// SCAN. Not thread-safe.
+type ScanIterator struct {
+	client       *Client
+	match        string
+	count        int64
+	done         []bool              // slots covered by completed node walks
+	seen         map[string]struct{} // keys of slots that moved during a walk
+	nodeAddr     string
+	nodeSlots    []bool // slots owned by the node when the walk started
+	nodeStarted  bool
+	cursor       int64
+	resume       *scanResume
+	page         []string
+	pos, retries int
+
+	val string
+	err error
+}
+
+// scanResume is the position of an aborted node walk
+type scanResume struct {
+	addr   string
+	slots  []bool
+	cursor int64
+}
+
+// Scan returns an iterator over all keys in the cluster matching a pattern.
+// An empty match and a zero count use the server defaults.
+func (c *Client) Scan(match string, count int64) *ScanIterator {
+	return &ScanIterator{
+		client: c,
+		match:  match,
+		count:  count,
+		done:   make([]bool, HashSlots),
+		seen:   make(map[string]struct{}),
+	}
+}
+
+// Next advances the iterator, returns false when all keys have been
+// visited or an error occurred
+func (it *ScanIterator) Next() bool {
+	for {
+		// Return buffered keys first
+		for it.pos < len(it.page) {
+			key := it.page[it.pos]
+			it.pos++
+
+			if it.visit(key) {
+				it.val = key
+				return true
+			}
+		}
+
+		if it.err != nil {
+			return false
+		}
+
+		// Node walk completed, mark its slots as done
+		if it.nodeStarted && it.cursor == 0 {
+			it.complete()
+		}
+
+		// Pick the next node to walk
+		if it.nodeAddr == "" {
+			addr := it.client.nextScanAddr(it.done)
+			if addr == "" {
+				return false
+			}
+			it.start(addr)
+		}
+
+		it.fetch()
+	}
+}
+
+// Val returns the current key
+func (it *ScanIterator) Val() string {
+	return it.val
+}
+
+// Err returns the error, if any
+func (it *ScanIterator) Err() error {
+	return it.err
+}
+
+// ForEach calls fn for each key, stops on the first error
+func (it *ScanIterator) ForEach(fn func(key string) error) error {
+	for it.Next() {
+		if err := fn(it.Val()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Checks if a key should be returned. Keys of slots that moved away are
+// recorded, the slots are scanned again on their new masters.
+func (it *ScanIterator) visit(key string) bool {
+	slot := HashSlot(key)
+	if it.done[slot] || !it.nodeSlots[slot] {
+		return false
+	}
+	if _, ok := it.seen[key]; ok {
+		return false
+	}
+	if it.client.topo().slotAddr(slot) != it.nodeAddr {
+		it.seen[key] = struct{}{}
+	}
+	return true
+}
+
+// Starts the walk of a node, snapshots the slots it owns. Resumes an
+// aborted walk of the same node.
+func (it *ScanIterator) start(addr string) {
+	it.nodeAddr = addr
+	if r := it.resume; r != nil && r.addr == addr {
+		it.nodeSlots, it.cursor, it.nodeStarted = r.slots, r.cursor, true
+	} else {
+		it.nodeSlots, it.cursor, it.nodeStarted = it.client.topo().masterSlots(addr), 0, false
+	}
+	it.resume = nil
+}
+
+// Fetches the next page of keys from the current node
+func (it *ScanIterator) fetch() {
+	if err := it.client.ctx.Err(); err != nil {
+		it.err = err
+		return
+	}
+
+	cmd := redis.NewScanCmd(scanArgs([]string{"SCAN"}, it.cursor, it.match, it.count)...)
+	it.client.processAddr(it.nodeAddr, cmd)
+
+	cursor, keys, err := cmd.Result()
+	if err != nil {
+		it.abort(err)
+		return
+	}
+
+	it.cursor, it.page, it.pos = cursor, keys, 0
+	it.nodeStarted = true
+	it.retries = 0
+}
+
+// Marks the slots the current node owned during the whole walk as
+// done, slots that moved away are scanned again on their new masters
+func (it *ScanIterator) complete() {
+	topo := it.client.topo()
+	for slot, owned := range it.nodeSlots {
+		if owned && topo.slotAddr(slot) == it.nodeAddr {
+			it.done[slot] = true
+		}
+	}
+
+	it.nodeAddr = ""
+	it.nodeSlots = nil
+}
+
+// Aborts the walk of the current node. On connection errors and
+// redirects, forces a slot cache reload and remembers the cursor, so the
+// walk can resume if the node still owns the slots.
+func (it *ScanIterator) abort(err error) {
+	_, moved := parseError(err).(*MovedError)
+	if !isConnError(err) && !moved {
+		it.err = err
+		return
+	}
+
+	if it.retries++; it.retries > MaxRedirects {
+		it.err = err
+		return
+	}
+
+	if it.nodeStarted && it.cursor != 0 {
+		it.resume = &scanResume{addr: it.nodeAddr, slots: it.nodeSlots, cursor: it.cursor}
+	}
+	it.nodeAddr = ""
+	it.nodeSlots = nil
+	if err := it.client.awaitReload(); err != nil {
+		it.err = err
+	}
+}
+
+// Finds the master of the first slot that has not been scanned yet
+func (c *Client) nextScanAddr(done []bool) string {
+	if c.reloadDue() {
+		c.reload(c.ctx)
+	}
+
+	for slot, addrs := range c.topo().slots {
+		if !done[slot] && len(addrs) > 0 {
+			return addrs[0]
+		}
+	}
+	return ""
+}
+
+// Applies a single command to a specific node
+func (c *Client) processAddr(addr string, cmd redis.Cmder) {
+	c.conns.Fetch(addr, c.connectTo).Process(cmd)
+}
+
+//------------------------------------------------------------------------------
+
+// CursorIterator iterates over the elements of a single hash, set or
+// sorted set, using HSCAN, SSCAN or ZSCAN. For hashes, values alternate
+// between fields and their values, for sorted sets between members and
+// their scores. Not thread-safe.
+type CursorIterator struct {
+	client  *Client
+	name    string
+	key     string
+	match   string
+	count   int64
+	cursor  int64
+	page    []string
+	pos     int
+	started bool
+
+	val string
+	err error
+}
+
+// HScan returns an iterator over the fields and values of a hash
+func (c *Client) HScan(key, match string, count int64) *CursorIterator {
+	return c.cursorIterator("HSCAN", key, match, count)
+}
+
+// SScan returns an iterator over the members of a set
+func (c *Client) SScan(key, match string, count int64) *CursorIterator {
+	return c.cursorIterator("SSCAN", key, match, count)
+}
+
+// ZScan returns an iterator over the members and scores of a sorted set
+func (c *Client) ZScan(key, match string, count int64) *CursorIterator {
+	return c.cursorIterator("ZSCAN", key, match, count)
+}
+
+func (c *Client) cursorIterator(name, key, match string, count int64) *CursorIterator {
+	return &CursorIterator{
+		client: c,
+		name:   name,
+		key:    key,
+		match:  match,
+		count:  count,
+	}
+}
+
+// Next advances the iterator, returns false when all elements have been
+// visited or an error occurred
+func (it *CursorIterator) Next() bool {
+	for {
+		if it.pos < len(it.page) {
+			it.val = it.page[it.pos]
+			it.pos++
+			return true
+		}
+
+		if it.err != nil || (it.started && it.cursor == 0) {
+			return false
+		}
+
+		// Cursors are only valid on the node that issued them,
+		// always use the master
+		cmd := redis.NewScanCmd(scanArgs([]string{it.name, it.key}, it.cursor, it.match, it.count)...)
+		it.client.Process(HashSlot(it.key), cmd)
+
+		cursor, page, err := cmd.Result()
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.cursor, it.page, it.pos = cursor, page, 0
+		it.started = true
+	}
+}
+
+// Val returns the current element
+func (it *CursorIterator) Val() string {
+	return it.val
+}
+
+// Err returns the error, if any
+func (it *CursorIterator) Err() error {
+	return it.err
+}
+
+// ForEach calls fn for each element, stops on the first error
+func (it *CursorIterator) ForEach(fn func(val string) error) error {
+	for it.Next() {
+		if err := fn(it.Val()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Appends cursor, MATCH and COUNT arguments
+func scanArgs(args []string, cursor int64, match string, count int64) []string {
+	args = append(args, strconv.FormatInt(cursor, 10))
+	if match != "" {
+		args = append(args, "MATCH", match)
+	}
+	if count > 0 {
+		args = append(args, "COUNT", strconv.FormatInt(count, 10))
+	}
+	return args
+}
@@ -0,0 +1,194 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScanIterator", func() {
+	var client *Client
+	var subject *ScanIterator
+
+	BeforeEach(func() {
+		client = newClient(&Options{Addrs: []string{"127.0.0.1:6379"}})
+		client.reset()
+		client.cacheSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7002"}},
+			{min: 8192, max: 16383, addrs: []string{"127.0.0.1:7001", "127.0.0.1:7003"}},
+		})
+		subject = client.Scan("*", 100)
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("should walk masters in slot order", func() {
+		Expect(client.nextScanAddr(subject.done)).To(Equal("127.0.0.1:7000"))
+
+		subject.start("127.0.0.1:7000")
+		subject.complete()
+		Expect(subject.done[0]).To(BeTrue())
+		Expect(subject.done[8191]).To(BeTrue())
+		Expect(subject.done[8192]).To(BeFalse())
+		Expect(subject.nodeAddr).To(Equal(""))
+		Expect(client.nextScanAddr(subject.done)).To(Equal("127.0.0.1:7001"))
+
+		subject.start("127.0.0.1:7001")
+		subject.complete()
+		Expect(client.nextScanAddr(subject.done)).To(Equal(""))
+	})
+
+	It("should only mark slots owned during the whole walk", func() {
+		subject.start("127.0.0.1:7000")
+		Expect(subject.visit("bar")).To(BeTrue())
+		Expect(subject.visit("foo")).To(BeFalse())
+
+		// Slots 0-99 moved away, 8192-8291 moved in during the walk
+		client.cacheSlots([]slotInfo{
+			{min: 0, max: 99, addrs: []string{"127.0.0.1:7001"}},
+			{min: 100, max: 8291, addrs: []string{"127.0.0.1:7000"}},
+			{min: 8292, max: 16383, addrs: []string{"127.0.0.1:7001"}},
+		})
+		Expect(subject.visit("key37")).To(BeTrue()) // slot 95
+		subject.complete()
+		Expect(subject.done[0]).To(BeFalse())
+		Expect(subject.done[99]).To(BeFalse())
+		Expect(subject.done[100]).To(BeTrue())
+		Expect(subject.done[8191]).To(BeTrue())
+		Expect(subject.done[8192]).To(BeFalse())
+		Expect(subject.seen).To(Equal(map[string]struct{}{"key37": {}}))
+		Expect(client.nextScanAddr(subject.done)).To(Equal("127.0.0.1:7001"))
+
+		subject.start("127.0.0.1:7001")
+		Expect(subject.visit("key37")).To(BeFalse())
+		Expect(subject.visit("key257")).To(BeTrue()) // slot 34
+	})
+
+	It("should not remember keys when the topology is unchanged", func() {
+		subject.start("127.0.0.1:7000")
+		Expect(subject.visit("bar")).To(BeTrue())
+		subject.complete()
+		Expect(subject.seen).To(BeEmpty())
+	})
+
+	It("should keep memory bounded during normal walks", func() {
+		node := newFakeNode()
+		defer node.Close()
+
+		client.cacheSlots([]slotInfo{{min: 0, max: 16383, addrs: []string{node.Addr()}}})
+		go func() {
+			defer GinkgoRecover()
+
+			for page := 1; page <= 50; page++ {
+				Eventually(node.cmds).Should(Receive())
+				cursor := strconv.Itoa(page % 50)
+				node.Reply(fmt.Sprintf("*2\r\n$%d\r\n%s\r\n*2\r\n$7\r\nkey:%03d\r\n$7\r\nkey:%03d\r\n",
+					len(cursor), cursor, 2*page, 2*page+1))
+			}
+		}()
+
+		n := 0
+		Expect(subject.ForEach(func(string) error {
+			n++
+			return nil
+		})).To(Succeed())
+		Expect(n).To(Equal(100))
+		Expect(subject.seen).To(BeEmpty())
+		Expect(subject.resume).To(BeNil())
+	})
+
+	It("should force rate-limited reloads on failures", func() {
+		atomic.StoreInt64(&client.reloadedAt, time.Now().UnixNano())
+		subject.start("127.0.0.1:7000")
+
+		start := time.Now()
+		subject.abort(io.EOF)
+		Expect(subject.Err()).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		Expect(client.reloadDue()).To(BeTrue())
+		Expect(subject.nodeAddr).To(Equal(""))
+	})
+
+	It("should resume aborted walks", func() {
+		subject.start("127.0.0.1:7000")
+		subject.cursor, subject.nodeStarted = 12, true
+		subject.abort(io.EOF)
+		Expect(subject.resume).NotTo(BeNil())
+
+		subject.start("127.0.0.1:7000")
+		Expect(subject.cursor).To(Equal(int64(12)))
+		Expect(subject.nodeStarted).To(BeTrue())
+		Expect(subject.resume).To(BeNil())
+		Expect(subject.visit("bar")).To(BeTrue())
+
+		subject.cursor = 13
+		subject.abort(io.EOF)
+		subject.start("127.0.0.1:7001")
+		Expect(subject.cursor).To(Equal(int64(0)))
+		Expect(subject.nodeStarted).To(BeFalse())
+		Expect(subject.resume).To(BeNil())
+	})
+
+	It("should not return keys twice", func() {
+		subject.start("127.0.0.1:7000")
+		Expect(subject.visit("foo")).To(BeFalse()) // owned by 7001
+
+		subject.done[HashSlot("bar")] = true
+		Expect(subject.visit("bar")).To(BeFalse())
+
+		subject.seen["baz"] = struct{}{}
+		Expect(subject.visit("baz")).To(BeFalse())
+	})
+
+	It("should iterate page buffers", func() {
+		subject.start("127.0.0.1:7000")
+		subject.page = []string{"bar", "foo", "baz"}
+		subject.err = errNoAddresses
+
+		var keys []string
+		for subject.Next() {
+			keys = append(keys, subject.Val())
+		}
+		Expect(keys).To(Equal([]string{"bar", "baz"}))
+		Expect(subject.Err()).To(Equal(errNoAddresses))
+	})
+
+})
+
+var _ = Describe("CursorIterator", func() {
+	var client *Client
+
+	BeforeEach(func() {
+		client = newClient(&Options{Addrs: []string{"127.0.0.1:6379"}})
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("should iterate pages until the cursor is exhausted", func() {
+		subject := client.HScan("key", "", 0)
+		subject.page = []string{"field", "value"}
+		subject.started = true
+
+		var vals []string
+		Expect(subject.ForEach(func(val string) error {
+			vals = append(vals, val)
+			return nil
+		})).To(Succeed())
+		Expect(vals).To(Equal([]string{"field", "value"}))
+	})
+
+	It("should build arguments", func() {
+		Expect(scanArgs([]string{"SSCAN", "key"}, 0, "", 0)).To(Equal([]string{"SSCAN", "key", "0"}))
+		Expect(scanArgs([]string{"SCAN"}, 12, "foo*", 100)).To(Equal([]string{"SCAN", "12", "MATCH", "foo*", "COUNT", "100"}))
+	})
+
+})
@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"gopkg.in/redis.v2"
+)
+
+var errNoMasters = errors.New("redis cluster: no known masters")
+
+// Script wraps a Lua script. Scripts are run via EVALSHA on the
+// node serving the slot of the first key and transparently
+// fall back to EVAL if the node has not cached the script yet.
+type Script struct {
+	src, hash string
+}
+
+// NewScript creates a new script
+func NewScript(src string) *Script {
+	h := sha1.New()
+	io.WriteString(h, src)
+	return &Script{
+		src:  src,
+		hash: hex.EncodeToString(h.Sum(nil)),
+	}
+}
+
+// Hash returns the SHA1 digest of the script
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Load loads the script on every master
+func (s *Script) Load(c *Client) *redis.StringCmd {
+	return c.ScriptLoad(s.src)
+}
+
+// Exists checks if the script exists on every master
+func (s *Script) Exists(c *Client) *redis.BoolSliceCmd {
+	return c.ScriptExists(s.hash)
+}
+
+// Eval evaluates the script
+func (s *Script) Eval(c *Client, keys []string, args []string) *redis.Cmd {
+	return c.Eval(s.src, keys, args)
+}
+
+// EvalSha evaluates the cached script by its hash
+func (s *Script) EvalSha(c *Client, keys []string, args []string) *redis.Cmd {
+	return c.EvalSha(s.hash, keys, args)
+}
+
+// Run evaluates the cached script by its hash and falls back to
+// EVAL on a NOSCRIPT reply
+func (s *Script) Run(c *Client, keys []string, args []string) *redis.Cmd {
+	cmd := s.EvalSha(c, keys, args)
+	if err := cmd.Err(); err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT ") {
+		return s.Eval(c, keys, args)
+	}
+	return cmd
+}
+
+//------------------------------------------------------------------------------
+
+// ScriptExists checks the scripts (by hash) on every master. It returns the
+// reply of the first master that is missing any of the scripts, or the reply
+// of the last master if all scripts exist everywhere.
+func (c *Client) ScriptExists(scripts ...string) *redis.BoolSliceCmd {
+	args := append([]string{"SCRIPT", "EXISTS"}, scripts...)
+	cmds := c.processMasters(func() redis.Cmder {
+		return redis.NewBoolSliceCmd(args...)
+	})
+	for _, cmd := range cmds {
+		cmd := cmd.(*redis.BoolSliceCmd)
+		if cmd.Err() != nil {
+			return cmd
+		}
+		for _, ok := range cmd.Val() {
+			if !ok {
+				return cmd
+			}
+		}
+	}
+	return cmds[len(cmds)-1].(*redis.BoolSliceCmd)
+}
+
+// ScriptFlush flushes the script cache of every master. It returns the
+// reply of the first master that failed, if any.
+func (c *Client) ScriptFlush() *redis.StatusCmd {
+	cmd := firstFailed(c.processMasters(func() redis.Cmder {
+		return redis.NewStatusCmd("SCRIPT", "FLUSH")
+	}))
+	return cmd.(*redis.StatusCmd)
+}
+
+// ScriptLoad loads a script into the cache of every master. It returns the
+// reply of the first master that failed, if any.
+func (c *Client) ScriptLoad(script string) *redis.StringCmd {
+	cmd := firstFailed(c.processMasters(func() redis.Cmder {
+		return redis.NewStringCmd("SCRIPT", "LOAD", script)
+	}))
+	return cmd.(*redis.StringCmd)
+}
+
+// Applies a command to every known master, in parallel. Always
+// returns at least one command
+func (c *Client) processMasters(newCmd func() redis.Cmder) []redis.Cmder {
+	if err := c.ctx.Err(); err != nil {
+		cmd := newCmd()
+		setCmdErr(cmd, err)
+		return []redis.Cmder{cmd}
+	}
+	if c.reloadDue() {
+		c.reload(c.ctx)
+	}
+
+	addrs := c.topo().masterAddrs()
+	if len(addrs) == 0 {
+		cmd := newCmd()
+		setCmdErr(cmd, errNoMasters)
+		return []redis.Cmder{cmd}
+	}
+
+	cmds := make([]redis.Cmder, len(addrs))
+	var wait sync.WaitGroup
+	for i, addr := range addrs {
+		cmds[i] = newCmd()
+
+		wait.Add(1)
+		go func(addr string, cmd redis.Cmder) {
+			defer wait.Done()
+			c.conns.Fetch(addr, c.connectTo).Process(cmd)
+		}(addr, cmds[i])
+	}
+	wait.Wait()
+	return cmds
+}
+
+// Returns the first failed command, or the last one if all succeeded
+func firstFailed(cmds []redis.Cmder) redis.Cmder {
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			return cmd
+		}
+	}
+	return cmds[len(cmds)-1]
+}
@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("Script", func() {
+	fake := newFakeCluster()
+
+	It("should calculate hashes", func() {
+		script := NewScript("return 1")
+		Expect(script.Hash()).To(Equal("e0e1f9fabfc9d4800c877a703b823ac0578ff8db"))
+	})
+
+	It("should run cached scripts on the slot of the first key", func() {
+		script := NewScript("return 1")
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"EVALSHA", script.Hash(), "2", "foo", "{foo}bar", "x"})))
+			fake.nodes[1].Reply(":1\r\n")
+		}()
+
+		cmd := script.Run(fake.client, []string{"foo", "{foo}bar"}, []string{"x"})
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal(int64(1)))
+	})
+
+	It("should fall back to EVAL on NOSCRIPT replies", func() {
+		script := NewScript("return 2")
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"EVALSHA", script.Hash(), "1", "bar"})))
+			fake.nodes[0].Reply("-NOSCRIPT No matching script. Please use EVAL.\r\n")
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"EVAL", "return 2", "1", "bar"})))
+			fake.nodes[0].Reply(":2\r\n")
+		}()
+
+		cmd := script.Run(fake.client, []string{"bar"}, nil)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal(int64(2)))
+	})
+
+	It("should load scripts on every master", func() {
+		script := NewScript("return 1")
+		for _, node := range fake.nodes {
+			go func(node *fakeNode) {
+				defer GinkgoRecover()
+				Eventually(node.cmds).Should(Receive(Equal([]string{"SCRIPT", "LOAD", "return 1"})))
+				node.Reply("$40\r\n" + script.Hash() + "\r\n")
+			}(node)
+		}
+
+		cmd := script.Load(fake.client)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val()).To(Equal(script.Hash()))
+	})
+
+	It("should flush every master and return the first failure", func() {
+		for i, node := range fake.nodes {
+			go func(node *fakeNode, reply string) {
+				defer GinkgoRecover()
+				Eventually(node.cmds).Should(Receive(Equal([]string{"SCRIPT", "FLUSH"})))
+				node.Reply(reply)
+			}(node, []string{"+OK\r\n", "-ERR flush failed\r\n"}[i])
+		}
+		Expect(fake.client.ScriptFlush().Err()).To(MatchError("ERR flush failed"))
+	})
+
+	It("should fail without known masters", func() {
+		client := newClient(&Options{})
+		defer client.Close()
+
+		Expect(client.ScriptLoad("return 1").Err()).To(Equal(errNoMasters))
+		Expect(client.ScriptFlush().Err()).To(Equal(errNoMasters))
+		Expect(client.ScriptExists("e0e1f9fabfc9d4800c877a703b823ac0578ff8db").Err()).To(Equal(errNoMasters))
+	})
+
+	It("should pick the first failed command", func() {
+		ok := redis.NewStatusCmd("PING")
+		failed := redis.NewStatusCmd("PING")
+		setCmdErr(failed, errNoMasters)
+		Expect(firstFailed([]redis.Cmder{ok, failed})).To(Equal(failed))
+		Expect(firstFailed([]redis.Cmder{ok})).To(Equal(ok))
+	})
+
+})
@@ -0,0 +1,435 @@
+package cluster
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"gopkg.in/redis.v2"
+)
+
+var errUnservedSlot = errors.New("redis cluster: slot is not served by any node")
+
+// ShardedPubSub subscribes to shard channels (Redis 7+). Channels are
+// routed by hash slot, with one connection per node. When slots move,
+// channels are re-subscribed on their new nodes. Messages from all nodes
+// are merged into a single Go channel. Thread-safe.
+type ShardedPubSub struct {
+	client  *Client
+	msgs    chan *redis.Message
+	notify  chan struct{}
+	closing chan struct{}
+	wait    sync.WaitGroup
+
+	lock     sync.Mutex
+	channels map[string]string // node address by channel, empty if unassigned
+	nodes    map[string]*shardConn
+	err      error
+	closed   bool
+}
+
+type shardConn struct {
+	addr    string
+	cn      net.Conn
+	pending [][]string // channels of unconfirmed SSUBSCRIBE commands
+}
+
+// SSubscribe creates a sharded subscriber and subscribes to channels.
+// The subscriber is returned even if subscribing failed, failed
+// channels are retried in the background.
+func (c *Client) SSubscribe(channels ...string) (*ShardedPubSub, error) {
+	p := &ShardedPubSub{
+		client:   c,
+		msgs:     make(chan *redis.Message, 100),
+		notify:   make(chan struct{}, 1),
+		closing:  make(chan struct{}),
+		channels: make(map[string]string),
+		nodes:    make(map[string]*shardConn),
+	}
+	c.watchTopology(p.notify)
+
+	p.wait.Add(1)
+	go p.run()
+
+	return p, p.SSubscribe(channels...)
+}
+
+// Channel returns the channel of received messages. It is closed
+// when the subscriber is closed.
+func (p *ShardedPubSub) Channel() <-chan *redis.Message {
+	return p.msgs
+}
+
+// Err returns the last error reply of a node, e.g. when a subscription
+// was refused. The affected channels are retried on the next change of
+// subscriptions or topology.
+func (p *ShardedPubSub) Err() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.err
+}
+
+// SSubscribe subscribes to shard channels
+func (p *ShardedPubSub) SSubscribe(channels ...string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return errClosedPubSub
+	}
+	for _, ch := range channels {
+		if _, ok := p.channels[ch]; !ok {
+			p.channels[ch] = ""
+		}
+	}
+	return p.sync()
+}
+
+// SUnsubscribe unsubscribes from shard channels
+func (p *ShardedPubSub) SUnsubscribe(channels ...string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return errClosedPubSub
+	}
+	for _, ch := range channels {
+		addr, ok := p.channels[ch]
+		if !ok {
+			continue
+		}
+		delete(p.channels, ch)
+
+		if sc := p.nodes[addr]; sc != nil {
+			if err := p.write(sc, "SUNSUBSCRIBE", ch); err != nil {
+				p.drop(sc)
+			}
+		}
+	}
+	p.prune()
+	return nil
+}
+
+// Close closes all connections and the message channel
+func (p *ShardedPubSub) Close() error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.closing)
+	for _, sc := range p.nodes {
+		p.drop(sc)
+	}
+	p.lock.Unlock()
+
+	p.client.unwatchTopology(p.notify)
+	p.wait.Wait()
+	close(p.msgs)
+	return nil
+}
+
+// Re-routes channels on topology changes and lost subscriptions
+func (p *ShardedPubSub) run() {
+	defer p.wait.Done()
+
+	for {
+		select {
+		case <-p.closing:
+			return
+		case <-p.notify:
+		}
+
+		// Lost subscriptions indicate a stale slot cache
+		p.lock.Lock()
+		stale := p.unassigned()
+		p.lock.Unlock()
+		if stale {
+			_ = p.client.reload(p.client.ctx)
+		}
+
+		p.lock.Lock()
+		err := p.sync()
+		p.lock.Unlock()
+		if err != nil {
+			time.AfterFunc(p.client.opts.minReloadInterval(), p.signal)
+		}
+	}
+}
+
+// Subscribes unassigned channels and channels that moved on the nodes
+// serving their slots. Must be called with the lock held.
+func (p *ShardedPubSub) sync() (err error) {
+	if p.closed {
+		return errClosedPubSub
+	}
+
+	// Group moved channels by node and slot, a single SSUBSCRIBE
+	// only accepts channels of the same slot
+	topo := p.client.topo()
+	moved := make(map[string]map[int][]string)
+	for ch, addr := range p.channels {
+		slot := HashSlot(ch)
+		next := topo.slotAddr(slot)
+		if next == addr && addr != "" {
+			continue
+		}
+
+		if sc := p.nodes[addr]; sc != nil {
+			if err := p.write(sc, "SUNSUBSCRIBE", ch); err != nil {
+				p.drop(sc)
+			}
+		}
+		p.channels[ch] = ""
+
+		if next == "" {
+			err = errUnservedSlot
+			continue
+		}
+		if moved[next] == nil {
+			moved[next] = make(map[int][]string)
+		}
+		moved[next][slot] = append(moved[next][slot], ch)
+	}
+
+	for addr, slots := range moved {
+		sc, e := p.node(addr)
+		if e == errClosedPubSub {
+			return e
+		} else if e != nil {
+			err = e
+			continue
+		}
+		for _, channels := range slots {
+			// Channels may have changed while dialing
+			if channels = p.pendingChannels(channels); len(channels) == 0 {
+				continue
+			}
+			if e := p.write(sc, append([]string{"SSUBSCRIBE"}, channels...)...); e != nil {
+				err = e
+				p.drop(sc)
+				break
+			}
+			for _, ch := range channels {
+				p.channels[ch] = addr
+			}
+			sc.pending = append(sc.pending, channels)
+		}
+	}
+
+	p.prune()
+	return
+}
+
+// Filters channels that are still subscribed but unassigned. Must be
+// called with the lock held.
+func (p *ShardedPubSub) pendingChannels(channels []string) []string {
+	res := channels[:0]
+	for _, ch := range channels {
+		if addr, ok := p.channels[ch]; ok && addr == "" {
+			res = append(res, ch)
+		}
+	}
+	return res
+}
+
+// Returns the connection to a node, dials if necessary. Must be
+// called with the lock held, which is released while dialing.
+func (p *ShardedPubSub) node(addr string) (*shardConn, error) {
+	if sc, ok := p.nodes[addr]; ok {
+		return sc, nil
+	}
+
+	p.lock.Unlock()
+	cn, err := p.client.opts.dial(addr)
+	p.lock.Lock()
+
+	if err != nil {
+		p.client.forceReloadOnNextCommand()
+		return nil, err
+	}
+	if p.closed {
+		cn.Close()
+		return nil, errClosedPubSub
+	}
+	if sc, ok := p.nodes[addr]; ok {
+		cn.Close()
+		return sc, nil
+	}
+
+	sc := &shardConn{addr: addr, cn: cn}
+	p.nodes[addr] = sc
+
+	p.wait.Add(1)
+	go p.read(sc)
+	return sc, nil
+}
+
+// Reads replies from a node connection until it fails
+func (p *ShardedPubSub) read(sc *shardConn) {
+	defer p.wait.Done()
+
+	rd := bufio.NewReader(sc.cn)
+	for {
+		reply, err := readReply(rd)
+		if err != nil {
+			p.lost(sc)
+			return
+		}
+
+		switch v := reply.(type) {
+		case error:
+			p.redirected(sc, v)
+		case []interface{}:
+			if len(v) < 3 {
+				continue
+			}
+			kind, _ := v[0].(string)
+			ch, _ := v[1].(string)
+
+			switch kind {
+			case "smessage":
+				payload, _ := v[2].(string)
+				select {
+				case p.msgs <- &redis.Message{Channel: ch, Payload: payload}:
+				case <-p.closing:
+					return
+				}
+			case "ssubscribe":
+				p.subscribed(sc, ch)
+			case "sunsubscribe":
+				p.unsubscribed(sc, ch)
+			}
+		}
+	}
+}
+
+// Handles a failed node connection, all its channels are re-routed
+func (p *ShardedPubSub) lost(sc *shardConn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.drop(sc)
+}
+
+// Handles a subscription confirmation
+func (p *ShardedPubSub) subscribed(sc *shardConn, ch string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(sc.pending) == 0 || len(sc.pending[0]) == 0 || sc.pending[0][0] != ch {
+		return
+	}
+	if sc.pending[0] = sc.pending[0][1:]; len(sc.pending[0]) == 0 {
+		sc.pending = sc.pending[1:]
+	}
+}
+
+// Handles a server-side sunsubscribe, e.g. after a slot migration
+func (p *ShardedPubSub) unsubscribed(sc *shardConn, ch string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if addr, ok := p.channels[ch]; ok && addr == sc.addr {
+		p.channels[ch] = ""
+		p.signal()
+	}
+}
+
+// Handles error replies to SSUBSCRIBE, channels of moved slots are
+// re-routed. On other errors, the channels are unassigned and the
+// error is recorded.
+func (p *ShardedPubSub) redirected(sc *shardConn, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var channels []string
+	if len(sc.pending) != 0 {
+		channels, sc.pending = sc.pending[0], sc.pending[1:]
+	}
+
+	if e, ok := parseError(err).(*MovedError); ok {
+		for ch, addr := range p.channels {
+			if addr == sc.addr && HashSlot(ch) == e.Slot {
+				p.channels[ch] = ""
+			}
+		}
+		p.signal()
+		return
+	}
+
+	for _, ch := range channels {
+		if addr, ok := p.channels[ch]; ok && addr == sc.addr {
+			p.channels[ch] = ""
+		}
+	}
+	p.err = &NodeError{Addr: sc.addr, Err: parseError(err)}
+}
+
+// Sends a command to a node
+func (p *ShardedPubSub) write(sc *shardConn, args ...string) error {
+	if timeout := p.client.opts.WriteTimeout; timeout > 0 {
+		if err := sc.cn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+	}
+	return writeCmd(sc.cn, args...)
+}
+
+// Closes a node connection, its channels are re-routed. Must be called
+// with the lock held.
+func (p *ShardedPubSub) drop(sc *shardConn) {
+	sc.cn.Close()
+	if p.nodes[sc.addr] != sc {
+		return
+	}
+	delete(p.nodes, sc.addr)
+
+	unassigned := false
+	for ch, addr := range p.channels {
+		if addr == sc.addr {
+			p.channels[ch] = ""
+			unassigned = true
+		}
+	}
+	if unassigned {
+		p.signal()
+	}
+}
+
+// Closes connections to nodes without channels. Must be called
+// with the lock held.
+func (p *ShardedPubSub) prune() {
+	used := make(map[string]struct{}, len(p.nodes))
+	for _, addr := range p.channels {
+		used[addr] = struct{}{}
+	}
+	for addr, sc := range p.nodes {
+		if _, ok := used[addr]; !ok {
+			p.drop(sc)
+		}
+	}
+}
+
+// Checks if any channels are unassigned. Must be called with the
+// lock held.
+func (p *ShardedPubSub) unassigned() bool {
+	for _, addr := range p.channels {
+		if addr == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Wakes up the background routine
+func (p *ShardedPubSub) signal() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("ShardedPubSub", func() {
+	fake := newFakeCluster()
+	var subject *ShardedPubSub
+
+	BeforeEach(func() {
+		var err error
+		subject, err = fake.client.SSubscribe("bar", "foo") // slots 5061 and 12182
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "bar"})))
+		Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "foo"})))
+	})
+
+	AfterEach(func() {
+		subject.Close()
+	})
+
+	It("should merge messages from all fake.nodes", func() {
+		fake.nodes[0].Reply("*3\r\n$8\r\nsmessage\r\n$3\r\nbar\r\n$1\r\nx\r\n")
+		Eventually(subject.Channel()).Should(Receive(Equal(&redis.Message{Channel: "bar", Payload: "x"})))
+		fake.nodes[1].Reply("*3\r\n$8\r\nsmessage\r\n$3\r\nfoo\r\n$1\r\ny\r\n")
+		Eventually(subject.Channel()).Should(Receive(Equal(&redis.Message{Channel: "foo", Payload: "y"})))
+	})
+
+	It("should move channels when the topology changes", func() {
+		fake.route(fake.nodes[1], fake.nodes[1])
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SUNSUBSCRIBE", "bar"})))
+		Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "bar"})))
+	})
+
+	It("should re-subscribe after server-side unsubscribes", func() {
+		fake.nodes[0].Reply("*3\r\n$12\r\nsunsubscribe\r\n$3\r\nbar\r\n:0\r\n")
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "bar"})))
+	})
+
+	It("should re-subscribe on lost connections", func() {
+		fake.route(fake.nodes[0], fake.nodes[0])
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "foo"})))
+		Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"SUNSUBSCRIBE", "foo"})))
+
+		fake.nodes[0].Close()
+		fake.route(fake.nodes[1], fake.nodes[1])
+
+		var cmds [][]string
+		for len(cmds) < 2 {
+			var cmd []string
+			Eventually(fake.nodes[1].cmds).Should(Receive(&cmd))
+			cmds = append(cmds, cmd)
+		}
+		Expect(cmds).To(ConsistOf([]string{"SSUBSCRIBE", "bar"}, []string{"SSUBSCRIBE", "foo"}))
+	})
+
+	It("should re-subscribe remaining channels after failed writes", func() {
+		fake.route(fake.nodes[0], fake.nodes[0])
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "foo"})))
+		Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"SUNSUBSCRIBE", "foo"})))
+
+		subject.lock.Lock()
+		sc := subject.nodes[fake.nodes[0].Addr()]
+		sc.cn = &failingConn{Conn: sc.cn}
+		subject.lock.Unlock()
+
+		Expect(subject.SUnsubscribe("bar")).To(Succeed())
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "foo"})))
+	})
+
+	It("should track subscription confirmations", func() {
+		pending := func() [][]string {
+			subject.lock.Lock()
+			defer subject.lock.Unlock()
+			return subject.nodes[fake.nodes[0].Addr()].pending
+		}
+		Expect(pending()).To(Equal([][]string{{"bar"}}))
+
+		fake.nodes[0].Reply("*3\r\n$10\r\nssubscribe\r\n$3\r\nbar\r\n:1\r\n")
+		Eventually(pending).Should(BeEmpty())
+	})
+
+	It("should report refused subscriptions", func() {
+		fake.nodes[0].Reply("-NOPERM this user has no permissions to access one of the channels used as arguments\r\n")
+		Eventually(subject.Err).Should(MatchError(ContainSubstring("NOPERM")))
+		Expect(subject.Err()).To(BeAssignableToTypeOf(&NodeError{}))
+
+		subject.lock.Lock()
+		Expect(subject.channels).To(Equal(map[string]string{"bar": "", "foo": fake.nodes[1].Addr()}))
+		subject.lock.Unlock()
+
+		Expect(subject.SSubscribe("bar")).To(Succeed())
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SSUBSCRIBE", "bar"})))
+	})
+
+	It("should discard connections dialed after close", func() {
+		node := newFakeNode()
+		defer node.Close()
+
+		subject.lock.Lock()
+		defer subject.lock.Unlock()
+
+		subject.closed = true
+		_, err := subject.node(node.Addr())
+		Expect(err).To(Equal(errClosedPubSub))
+		Expect(subject.nodes).NotTo(HaveKey(node.Addr()))
+		subject.closed = false
+	})
+
+	It("should unsubscribe", func() {
+		Expect(subject.SUnsubscribe("bar")).To(Succeed())
+		Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"SUNSUBSCRIBE", "bar"})))
+		subject.lock.Lock()
+		defer subject.lock.Unlock()
+		Expect(subject.channels).To(HaveLen(1))
+	})
+
+	It("should close the message channel", func() {
+		Expect(subject.Close()).To(Succeed())
+		Eventually(subject.Channel()).Should(BeClosed())
+		Expect(subject.SSubscribe("baz")).To(Equal(errClosedPubSub))
+	})
+
+})
+
+// failingConn fails all writes
+type failingConn struct {
+	net.Conn
+}
+
+func (c *failingConn) Write(_ []byte) (int, error) {
+	return 0, &net.OpError{Op: "write", Net: "tcp", Err: errors.New("broken pipe")}
+}
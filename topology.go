@@ -0,0 +1,193 @@
+package cluster
+
+import "math/rand"
+
+// topology is an immutable snapshot of the known nodes and the slot
+// table. Reloads build a new snapshot and swap it in, requests read
+// the current one without locking.
+type topology struct {
+	addrs []string   // known node addresses, shuffled
+	slots [][]string // master and replica addresses by hash slot
+}
+
+func newTopology(addrs []string) *topology {
+	return &topology{addrs: addrs, slots: newSlotTable(nil)}
+}
+
+// Returns the current topology snapshot
+func (c *Client) topo() *topology {
+	return c.topology.Load().(*topology)
+}
+
+// Registers a channel that is notified, without blocking, whenever
+// a new topology is installed
+func (c *Client) watchTopology(ch chan<- struct{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.watchers == nil {
+		c.watchers = make(map[chan<- struct{}]struct{})
+	}
+	c.watchers[ch] = struct{}{}
+}
+
+// Unregisters a channel
+func (c *Client) unwatchTopology(ch chan<- struct{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.watchers, ch)
+}
+
+// Returns a new snapshot with the slot table built from infos and
+// unknown nodes added to the list of addresses
+func (t *topology) withSlots(infos []slotInfo) *topology {
+	addrs := make([]string, len(t.addrs))
+	copy(addrs, t.addrs)
+
+	// Create a map of known nodes
+	known := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		known[addr] = struct{}{}
+	}
+
+	// Store unknown nodes
+	for _, info := range infos {
+		for _, addr := range info.addrs {
+			if _, ok := known[addr]; !ok {
+				addrs = append(addrs, addr)
+				known[addr] = struct{}{}
+			}
+		}
+	}
+
+	// Shuffle addresses
+	for i := range addrs {
+		j := rand.Intn(i + 1)
+		addrs[i], addrs[j] = addrs[j], addrs[i]
+	}
+
+	return &topology{addrs: addrs, slots: newSlotTable(infos)}
+}
+
+// Find all known masters
+func (t *topology) masterAddrs() []string {
+	seen := make(map[string]struct{})
+	addrs := make([]string, 0)
+	for _, slot := range t.slots {
+		if len(slot) == 0 {
+			continue
+		}
+		if _, ok := seen[slot[0]]; !ok {
+			seen[slot[0]] = struct{}{}
+			addrs = append(addrs, slot[0])
+		}
+	}
+	return addrs
+}
+
+// Returns the addresses of all nodes serving slots
+func (t *topology) nodes() map[string]struct{} {
+	nodes := make(map[string]struct{})
+	for _, addrs := range t.slots {
+		for _, addr := range addrs {
+			nodes[addr] = struct{}{}
+		}
+	}
+	return nodes
+}
+
+// Find the current address for a hash slot
+func (t *topology) slotAddr(hashSlot int) string {
+	if addrs := t.slots[hashSlot]; len(addrs) > 0 {
+		return addrs[0]
+	}
+	return ""
+}
+
+// Returns the hash slots served by a master
+func (t *topology) masterSlots(addr string) []bool {
+	slots := make([]bool, len(t.slots))
+	for slot, addrs := range t.slots {
+		slots[slot] = len(addrs) > 0 && addrs[0] == addr
+	}
+	return slots
+}
+
+// Find the address to retry after a connection error. Prefers
+// the (untried) master of the hash slot before any other address
+func (t *topology) retryAddr(hashSlot int, tried map[string]struct{}) string {
+	if addr := t.slotAddr(hashSlot); addr != "" {
+		if _, ok := tried[addr]; !ok {
+			return addr
+		}
+	}
+	return t.nextAddr(tried)
+}
+
+// Find the next untried address
+func (t *topology) nextAddr(tried map[string]struct{}) string {
+	for _, addr := range t.addrs {
+		if _, ok := tried[addr]; !ok {
+			return addr
+		}
+	}
+	return ""
+}
+
+// Builds a slot table from slot infos
+func newSlotTable(infos []slotInfo) [][]string {
+	slots := make([][]string, HashSlots)
+	for _, info := range infos {
+		for i := info.min; i <= info.max; i++ {
+			slots[i] = info.addrs
+		}
+	}
+	return slots
+}
+
+//------------------------------------------------------------------------------
+
+// SlotRange is a range of hash slots, served by a master and its replicas
+type SlotRange struct {
+	Min, Max int
+	Master   string
+	Replicas []string
+}
+
+// Topology returns the ranges of the currently known slots, ordered by slot
+func (c *Client) Topology() []SlotRange {
+	return slotRanges(c.topo().slots, nil)
+}
+
+// NodeForKey returns the address of the master serving a key
+func (c *Client) NodeForKey(key string) string {
+	return c.topo().slotAddr(HashSlot(key))
+}
+
+// NodeForSlot returns the address of the master serving a hash slot
+func (c *Client) NodeForSlot(hashSlot int) string {
+	if hashSlot < 0 || hashSlot >= HashSlots {
+		return ""
+	}
+	return c.topo().slotAddr(hashSlot)
+}
+
+// Groups consecutive slots with the same addresses into ranges. Skips
+// slots that are not served or, if given, not selected
+func slotRanges(slots [][]string, selected func(int) bool) []SlotRange {
+	var ranges []SlotRange
+	for min := 0; min < len(slots); {
+		max := min
+		for max+1 < len(slots) && equalAddrs(slots[max+1], slots[min]) && (selected == nil || selected(max+1) == selected(min)) {
+			max++
+		}
+		if addrs := slots[min]; len(addrs) > 0 && (selected == nil || selected(min)) {
+			replicas := make([]string, len(addrs)-1)
+			copy(replicas, addrs[1:])
+			ranges = append(ranges, SlotRange{Min: min, Max: max, Master: addrs[0], Replicas: replicas})
+		}
+		min = max + 1
+	}
+	return ranges
+}
@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Topology", func() {
+	var subject *Client
+
+	BeforeEach(func() {
+		subject = newClient(&Options{Addrs: []string{"127.0.0.1:6379"}})
+		subject.cacheSlots([]slotInfo{
+			{min: 0, max: 8191, addrs: []string{"127.0.0.1:7000", "127.0.0.1:7002"}},
+			{min: 8192, max: 12287, addrs: []string{"127.0.0.1:7001"}},
+			{min: 12289, max: 16383, addrs: []string{"127.0.0.1:7001"}},
+		})
+	})
+
+	AfterEach(func() {
+		subject.Close()
+	})
+
+	It("should return slot ranges", func() {
+		Expect(subject.Topology()).To(Equal([]SlotRange{
+			{Min: 0, Max: 8191, Master: "127.0.0.1:7000", Replicas: []string{"127.0.0.1:7002"}},
+			{Min: 8192, Max: 12287, Master: "127.0.0.1:7001", Replicas: []string{}},
+			{Min: 12289, Max: 16383, Master: "127.0.0.1:7001", Replicas: []string{}},
+		}))
+	})
+
+	It("should return copies", func() {
+		subject.Topology()[0].Replicas[0] = "127.0.0.1:9999"
+		Expect(subject.Topology()[0].Replicas).To(Equal([]string{"127.0.0.1:7002"}))
+	})
+
+	It("should return empty topologies", func() {
+		subject.reset()
+		Expect(subject.Topology()).To(BeEmpty())
+	})
+
+	It("should find nodes for keys and slots", func() {
+		Expect(subject.NodeForKey("foo")).To(Equal("127.0.0.1:7001")) // slot 12182
+		Expect(subject.NodeForKey("bar")).To(Equal("127.0.0.1:7000")) // slot 5061
+		Expect(subject.NodeForSlot(0)).To(Equal("127.0.0.1:7000"))
+		Expect(subject.NodeForSlot(12288)).To(Equal(""))
+		Expect(subject.NodeForSlot(-1)).To(Equal(""))
+		Expect(subject.NodeForSlot(HashSlots)).To(Equal(""))
+	})
+
+})
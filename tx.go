@@ -0,0 +1,234 @@
+package cluster
+
+import (
+	"errors"
+
+	"gopkg.in/redis.v2"
+)
+
+var (
+	errCrossSlotTx = CrossSlotError("CROSSSLOT transaction keys must hash to the same slot")
+	errNoWatchKeys = errors.New("redis cluster: no keys to watch")
+)
+
+// Tx is a transaction on the node serving a single hash slot. Commands
+// are applied immediately, unless issued inside Exec, where they are
+// queued and applied atomically with MULTI/EXEC. Commands for other slots
+// fail with a CrossSlotError. Not thread-safe.
+type Tx struct {
+	commandable
+
+	slot    int
+	multi   *redis.Multi
+	ask     bool
+	queuing bool
+
+	err      error // first cross-slot error
+	redirect error // first MOVED or ASK redirection
+}
+
+// Watch watches keys and calls fn with a transaction pinned to the node
+// serving their slot. All keys must hash to the same slot. When the slot
+// is moved or migrated before EXEC, fn is called again on the new node.
+func (c *Client) Watch(fn func(*Tx) error, keys ...string) error {
+	if len(keys) == 0 {
+		return errNoWatchKeys
+	}
+
+	slot := HashSlot(keys[0])
+	for _, key := range keys[1:] {
+		if HashSlot(key) != slot {
+			return errCrossSlotTx
+		}
+	}
+
+	return c.runTx(slot, func(tx *Tx) error {
+		cmd := redis.NewStatusCmd(append([]string{"WATCH"}, keys...)...)
+		if tx.Process(slot, cmd); cmd.Err() != nil {
+			return cmd.Err()
+		}
+		return fn(tx)
+	})
+}
+
+// Runs fn in a transaction on the node serving a slot. Calls fn
+// again on the new node when redirected.
+func (c *Client) runTx(slot int, fn func(*Tx) error) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if c.reloadDue() {
+		c.reload(c.ctx)
+	}
+
+	var err error
+	addr, ask := c.topo().slotAddr(slot), false
+	for attempt := 0; attempt < MaxRedirects; attempt++ {
+		if attempt > 0 {
+			if err := c.ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		tx := &Tx{slot: slot, multi: c.conns.Fetch(addr, c.connectTo).Multi(), ask: ask}
+		tx.commandable.process = tx.queue
+		err = fn(tx)
+		if tx.multi.Close() != nil {
+			tx.multi.Client.Close()
+		}
+
+		if tx.redirect == nil {
+			return err
+		}
+		switch e := parseError(tx.redirect).(type) {
+		case *MovedError:
+			c.forceReloadOnNextCommand()
+			addr, ask = c.opts.mapAddr(e.Addr), false
+		case *AskError:
+			addr, ask = c.opts.mapAddr(e.Addr), true
+		}
+	}
+	return err
+}
+
+// Exec queues the commands issued by f and applies them atomically.
+// Returns redis.TxFailedErr if a watched key was modified.
+func (tx *Tx) Exec(f func() error) ([]redis.Cmder, error) {
+	if tx.redirect != nil {
+		return nil, tx.redirect
+	}
+
+	// ASKING applies to the whole transaction
+	if tx.ask {
+		tx.multi.Process(redis.NewStatusCmd("ASKING"))
+	}
+
+	var ferr error
+	tx.queuing = true
+	cmds, err := tx.multi.Exec(func() error {
+		if ferr = f(); ferr == nil {
+			ferr = tx.err
+		}
+		return ferr
+	})
+	tx.queuing = false
+
+	// Multi keeps queuing after f failed, reset it
+	if ferr != nil {
+		_, _ = tx.multi.Exec(func() error { return nil })
+	}
+
+	tx.redirected(err)
+	return cmds, err
+}
+
+// Queues or applies a single command
+func (tx *Tx) queue(hashSlot int, cmd redis.Cmder, _ bool) {
+	if hashSlot != tx.slot {
+		setCmdErr(cmd, errCrossSlotTx)
+		if tx.err == nil {
+			tx.err = errCrossSlotTx
+		}
+		return
+	} else if tx.redirect != nil {
+		setCmdErr(cmd, tx.redirect)
+		return
+	}
+
+	if tx.queuing {
+		tx.multi.Process(cmd)
+		return
+	}
+
+	if tx.ask {
+		tx.multi.Process(redis.NewStatusCmd("ASKING"))
+	}
+	tx.multi.Process(cmd)
+	tx.redirected(cmd.Err())
+}
+
+// Records MOVED and ASK redirections
+func (tx *Tx) redirected(err error) {
+	if err == nil || tx.redirect != nil {
+		return
+	}
+	switch parseError(err).(type) {
+	case *MovedError, *AskError:
+		tx.redirect = err
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// TxPipeline queues commands for a single hash slot and applies them
+// atomically with MULTI/EXEC. Not thread-safe.
+type TxPipeline struct {
+	commandable
+
+	client *Client
+	slot   int
+	cmds   []redis.Cmder
+	err    error
+	closed bool
+}
+
+// TxPipeline creates a transactional pipeline for the slot of slotKey
+func (c *Client) TxPipeline(slotKey string) *TxPipeline {
+	pipe := &TxPipeline{client: c, slot: HashSlot(slotKey)}
+	pipe.commandable.process = pipe.queue
+	return pipe
+}
+
+// Queues a single command, commands for other slots fail
+// with a CrossSlotError
+func (p *TxPipeline) queue(hashSlot int, cmd redis.Cmder, _ bool) {
+	if hashSlot != p.slot {
+		setCmdErr(cmd, errCrossSlotTx)
+		if p.err == nil {
+			p.err = errCrossSlotTx
+		}
+	}
+	p.cmds = append(p.cmds, cmd)
+}
+
+// Close closes the pipeline
+func (p *TxPipeline) Close() error {
+	p.closed = true
+	return nil
+}
+
+// Discard drops all queued commands
+func (p *TxPipeline) Discard() error {
+	if p.closed {
+		return errClosedPipeline
+	}
+	p.cmds, p.err = p.cmds[:0], nil
+	return nil
+}
+
+// Exec applies all queued commands in a transaction on the node serving
+// the slot. Nothing is sent if a command belongs to another slot.
+// Exec always returns the list of commands.
+func (p *TxPipeline) Exec() ([]redis.Cmder, error) {
+	if p.closed {
+		return nil, errClosedPipeline
+	}
+
+	cmds, err := p.cmds, p.err
+	p.cmds, p.err = nil, nil
+	if err != nil || len(cmds) == 0 {
+		return cmds, err
+	}
+
+	err = p.client.runTx(p.slot, func(tx *Tx) error {
+		_, err := tx.Exec(func() error {
+			for _, cmd := range cmds {
+				cmd.Reset()
+				tx.Process(p.slot, cmd)
+			}
+			return nil
+		})
+		return err
+	})
+	return cmds, err
+}
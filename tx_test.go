@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/redis.v2"
+)
+
+var _ = Describe("Tx", func() {
+	fake := newFakeCluster()
+
+	BeforeEach(func() {
+		fake.route(fake.nodes[0], fake.nodes[0])
+	})
+
+	It("should refuse cross-slot transactions", func() {
+		pipe := fake.client.TxPipeline("{user1}")
+		pipe.Set("{user1}.name", "x")
+		get := pipe.Get("{user2}.name")
+
+		cmds, err := pipe.Exec()
+		Expect(err).To(Equal(errCrossSlotTx))
+		Expect(cmds).To(HaveLen(2))
+		Expect(get.Err()).To(Equal(errCrossSlotTx))
+
+		Expect(fake.client.Watch(func(*Tx) error { return nil }, "{user1}.a", "{user2}.b")).To(Equal(errCrossSlotTx))
+		Expect(fake.client.Watch(func(*Tx) error { return nil })).To(Equal(errNoWatchKeys))
+	})
+
+	It("should apply transactions", func() {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"WATCH", "foo"})))
+			fake.nodes[0].Reply("+OK\r\n")
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"GET", "foo"})))
+			fake.nodes[0].Reply("$1\r\n1\r\n")
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"EXEC"})))
+			fake.nodes[0].Reply("+OK\r\n+QUEUED\r\n*1\r\n+OK\r\n")
+		}()
+
+		var set *redis.StatusCmd
+		err := fake.client.Watch(func(tx *Tx) error {
+			n, err := tx.Get("foo").Int64()
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(func() error {
+				set = tx.Set("foo", "2")
+				return nil
+			})
+			Expect(n).To(Equal(int64(1)))
+			return err
+		}, "foo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(set.Val()).To(Equal("OK"))
+	})
+
+	It("should redo transactions on the new owner", func() {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"EXEC"})))
+			fake.nodes[0].Reply("+OK\r\n-MOVED 12182 " + fake.nodes[1].Addr() + "\r\n-EXECABORT Transaction discarded\r\n")
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"MULTI"})))
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"SET", "foo", "bar"})))
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"EXEC"})))
+			fake.nodes[1].Reply("+OK\r\n+QUEUED\r\n*1\r\n+OK\r\n")
+		}()
+
+		pipe := fake.client.TxPipeline("foo")
+		set := pipe.Set("foo", "bar")
+		cmds, err := pipe.Exec()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds).To(HaveLen(1))
+		Expect(set.Val()).To(Equal("OK"))
+	})
+
+	It("should send ASKING before redirected transactions", func() {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(fake.nodes[0].cmds).Should(Receive(Equal([]string{"EXEC"})))
+			fake.nodes[0].Reply("+OK\r\n-ASK 12182 " + fake.nodes[1].Addr() + "\r\n-EXECABORT Transaction discarded\r\n")
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"ASKING"})))
+			fake.nodes[1].Reply("+OK\r\n")
+			Eventually(fake.nodes[1].cmds).Should(Receive(Equal([]string{"EXEC"})))
+			fake.nodes[1].Reply("+OK\r\n+QUEUED\r\n*1\r\n+OK\r\n")
+		}()
+
+		pipe := fake.client.TxPipeline("foo")
+		set := pipe.Set("foo", "bar")
+		_, err := pipe.Exec()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(set.Val()).To(Equal("OK"))
+		Expect(fake.client.reloadDue()).To(BeFalse())
+	})
+
+})